@@ -0,0 +1,240 @@
+package top
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gorgonia.org/tensor"
+)
+
+// tiePolicy is a policy for breaking ties
+type tiePolicy int
+
+const (
+	tieFirst tiePolicy = iota
+	tieLast
+	tieRandom
+)
+
+// TieBreak determines which index is chosen by Argmax and Argmin when
+// multiple elements along a row tie for the extreme value.
+type TieBreak struct {
+	policy tiePolicy
+	rng    *rand.Rand
+}
+
+var (
+	// TieFirst breaks ties by choosing the first index with the
+	// extreme value
+	TieFirst = TieBreak{policy: tieFirst}
+
+	// TieLast breaks ties by choosing the last index with the
+	// extreme value
+	TieLast = TieBreak{policy: tieLast}
+)
+
+// TieRandom returns a TieBreak which breaks ties by choosing uniformly
+// at random between all indices with the extreme value, using src as
+// the source of randomness. Since src is not safe for concurrent use,
+// the returned TieBreak should not be used by multiple goroutines at
+// once.
+func TieRandom(src rand.Source) TieBreak {
+	return TieBreak{policy: tieRandom, rng: rand.New(src)}
+}
+
+// choose chooses one of the tied indices
+func (t TieBreak) choose(ties []int) int {
+	switch t.policy {
+	case tieLast:
+		return ties[len(ties)-1]
+
+	case tieRandom:
+		if len(ties) == 1 {
+			return ties[0]
+		}
+		return ties[t.rng.Intn(len(ties))]
+
+	default:
+		return ties[0]
+	}
+}
+
+// Argmax returns an int tensor containing the indices of the maximum
+// elements of t along axis. If keepdims is true, the returned tensor
+// has the same number of dimensions as t with axis having size 1, so
+// that it can be used directly as the indices argument of Gather.
+// Otherwise, axis is removed from the shape of the returned tensor.
+//
+// When multiple elements along axis tie for the maximum, the
+// index returned is chosen by tie. NaN values are considered larger
+// than any other value, so that the index of a NaN is returned if
+// any element along axis is NaN. An error is returned if axis has
+// length 0, since an empty axis has no maximum.
+//
+// Argmax works on tensors of type float64, float32, any int type, or
+// bool, where true is considered larger than false.
+func Argmax(t tensor.Tensor, axis int, keepdims bool,
+	tie TieBreak) (tensor.Tensor, error) {
+	out, err := argExtremum(t, axis, keepdims, tie, 1)
+	if err != nil {
+		return nil, fmt.Errorf("argmax: %v", err)
+	}
+	return out, nil
+}
+
+// Argmin returns an int tensor containing the indices of the minimum
+// elements of t along axis. NaN values are considered smaller than
+// any other value. See Argmax for more details.
+func Argmin(t tensor.Tensor, axis int, keepdims bool,
+	tie TieBreak) (tensor.Tensor, error) {
+	out, err := argExtremum(t, axis, keepdims, tie, -1)
+	if err != nil {
+		return nil, fmt.Errorf("argmin: %v", err)
+	}
+	return out, nil
+}
+
+// argExtremum returns the indices of the extreme elements of t along
+// axis. If sign is positive, the maximum elements are found, otherwise
+// the minimum elements are found.
+func argExtremum(t tensor.Tensor, axis int, keepdims bool, tie TieBreak,
+	sign int) (tensor.Tensor, error) {
+	if axis < 0 || axis >= len(t.Shape()) {
+		return nil, fmt.Errorf("axis out of range [%v] for tensor with "+
+			"%v dimensions", axis, len(t.Shape()))
+	}
+
+	shape := t.Shape()
+	if shape[axis] == 0 {
+		return nil, fmt.Errorf("cannot take the extremum of empty axis %v",
+			axis)
+	}
+	if shape.TotalSize() == 0 {
+		// There are no rows along axis to take the extremum of
+		return newTensor(reducedShape(shape, axis, keepdims), []int{}), nil
+	}
+
+	// NaNs should always be chosen as the extreme value, so they are
+	// considered larger when finding the maximum and smaller when
+	// finding the minimum
+	compare, err := comparer(t, sign > 0)
+	if err != nil {
+		return nil, err
+	}
+
+	starts, stride := axisRows(shape, axis)
+	indices := make([]int, len(starts))
+	ties := make([]int, 0, shape[axis])
+	for row, start := range starts {
+		ties = append(ties[:0], 0)
+		best := start
+		for i := 1; i < shape[axis]; i++ {
+			j := start + i*stride
+			switch c := sign * compare(j, best); {
+			case c > 0:
+				best = j
+				ties = append(ties[:0], i)
+			case c == 0:
+				ties = append(ties, i)
+			}
+		}
+		indices[row] = tie.choose(ties)
+	}
+
+	return newTensor(reducedShape(shape, axis, keepdims), indices), nil
+}
+
+// comparer returns a function which compares elements i and j of t
+// in row-major order, returning a positive value if element i is
+// larger, a negative value if element j is larger, and 0 otherwise.
+// For bool tensors, true is considered larger than false.
+// If nanHigh is true, NaN values are considered larger than all other
+// values, otherwise they are considered smaller than all other values.
+func comparer(t tensor.Tensor, nanHigh bool) (func(i, j int) int, error) {
+	switch {
+	case t.Dtype() == tensor.Float64:
+		data, _ := float64Data(t)
+		return func(i, j int) int {
+			return compareFloat64(data[i], data[j], nanHigh)
+		}, nil
+
+	case t.Dtype() == tensor.Float32:
+		data, _ := float32Data(t)
+		return func(i, j int) int {
+			return compareFloat64(float64(data[i]), float64(data[j]),
+				nanHigh)
+		}, nil
+
+	case t.Dtype() == tensor.Uint || t.Dtype() == tensor.Uint64:
+		// Compared natively, since values larger than the largest int
+		// would wrap around if converted
+		data, _ := uint64Data(t)
+		return func(i, j int) int {
+			switch {
+			case data[i] > data[j]:
+				return 1
+			case data[i] < data[j]:
+				return -1
+			default:
+				return 0
+			}
+		}, nil
+
+	case isIntDtype(t.Dtype()):
+		data, _ := intData(t)
+		return func(i, j int) int {
+			switch {
+			case data[i] > data[j]:
+				return 1
+			case data[i] < data[j]:
+				return -1
+			default:
+				return 0
+			}
+		}, nil
+
+	case t.Dtype() == tensor.Bool:
+		data, _ := boolData(t)
+		return func(i, j int) int {
+			switch {
+			case data[i] && !data[j]:
+				return 1
+			case !data[i] && data[j]:
+				return -1
+			default:
+				return 0
+			}
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown tensor type %v", t.Dtype())
+	}
+}
+
+// compareFloat64 compares a and b, returning a positive value if a is
+// larger, a negative value if b is larger, and 0 otherwise. If nanHigh
+// is true, NaN values are considered larger than all other values,
+// otherwise they are considered smaller than all other values.
+func compareFloat64(a, b float64, nanHigh bool) int {
+	nan := 1
+	if !nanHigh {
+		nan = -1
+	}
+
+	aNaN, bNaN := math.IsNaN(a), math.IsNaN(b)
+	switch {
+	case aNaN && bNaN:
+		return 0
+	case aNaN:
+		return nan
+	case bNaN:
+		return -nan
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	default:
+		return 0
+	}
+}
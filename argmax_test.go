@@ -0,0 +1,215 @@
+package top
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestArgmax(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 4},
+		tensor.WithBacking([]float64{1, 5, 5, 0, 3, math.NaN(), 2, 3}),
+	)
+
+	// Ties broken by first and last index
+	first := tensor.NewDense(
+		tensor.Int,
+		[]int{2},
+		tensor.WithBacking([]int{1, 1}),
+	)
+	last := tensor.NewDense(
+		tensor.Int,
+		[]int{2},
+		tensor.WithBacking([]int{2, 1}),
+	)
+
+	out, err := Argmax(in, 1, false, TieFirst)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(first) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", first, out)
+	}
+
+	out, err = Argmax(in, 1, false, TieLast)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(last) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", last, out)
+	}
+
+	// Argmax along axis 0 keeping dimensions
+	axis0 := tensor.NewDense(
+		tensor.Int,
+		[]int{1, 4},
+		tensor.WithBacking([]int{1, 1, 0, 1}),
+	)
+	out, err = Argmax(in, 0, true, TieFirst)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(axis0) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", axis0, out)
+	}
+
+	// Bool tensors, where true is larger than false
+	bools := tensor.NewDense(
+		tensor.Bool,
+		[]int{3, 3},
+		tensor.WithBacking([]bool{
+			false, true, true,
+			false, false, false,
+			true, false, true,
+		}),
+	)
+	boolTarget := tensor.NewDense(
+		tensor.Int,
+		[]int{3},
+		tensor.WithBacking([]int{1, 0, 0}),
+	)
+	out, err = Argmax(bools, 1, false, TieFirst)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(boolTarget) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", boolTarget, out)
+	}
+
+	// Illegal axis
+	if _, err := Argmax(in, 2, false, TieFirst); err == nil {
+		t.Error("expected error for illegal axis")
+	}
+
+	// Empty axis
+	empty := tensor.NewDense(tensor.Float64, []int{3, 0})
+	if _, err := Argmax(empty, 1, false, TieFirst); err == nil {
+		t.Error("expected error for empty axis")
+	}
+	out, err = Argmax(empty, 0, false, TieFirst)
+	if err != nil {
+		t.Error(err)
+	}
+	if out.Shape().TotalSize() != 0 {
+		t.Errorf("expected empty output but got shape %v", out.Shape())
+	}
+}
+
+func TestArgmaxUint64(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Uint64,
+		[]int{2, 3},
+		tensor.WithBacking([]uint64{
+			1, math.MaxUint64, 5,
+			math.MaxUint64 - 1, 0, math.MaxInt64,
+		}),
+	)
+
+	max := tensor.NewDense(
+		tensor.Int,
+		[]int{2},
+		tensor.WithBacking([]int{1, 0}),
+	)
+	out, err := Argmax(in, 1, false, TieFirst)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(max) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", max, out)
+	}
+
+	min := tensor.NewDense(
+		tensor.Int,
+		[]int{2},
+		tensor.WithBacking([]int{0, 1}),
+	)
+	out, err = Argmin(in, 1, false, TieFirst)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(min) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", min, out)
+	}
+}
+
+func TestArgmin(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Int32,
+		[]int{2, 3},
+		tensor.WithBacking([]int32{4, -1, -1, 7, 8, 2}),
+	)
+	target := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 1},
+		tensor.WithBacking([]int{1, 2}),
+	)
+
+	out, err := Argmin(in, 1, true, TieFirst)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+}
+
+// TestArgmaxRandomTies tests if ties are broken uniformly at random
+func TestArgmaxRandomTies(t *testing.T) {
+	const numTests int = 1000
+
+	in := tensor.NewDense(
+		tensor.Float32,
+		[]int{4},
+		tensor.WithBacking([]float32{2, 0, 2, 2}),
+	)
+
+	tie := TieRandom(rand.NewSource(1))
+	counts := make(map[int]int)
+	for i := 0; i < numTests; i++ {
+		out, err := Argmax(in, 0, true, tie)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[out.Data().([]int)[0]]++
+	}
+
+	if counts[1] != 0 {
+		t.Errorf("non-maximal index chosen %v times", counts[1])
+	}
+	for _, index := range []int{0, 2, 3} {
+		if counts[index] < numTests/6 {
+			t.Errorf("index %v chosen only %v times", index, counts[index])
+		}
+	}
+}
+
+// TestGatherArgmax tests if gathering along the indices returned by
+// Argmax results in the maximum values
+func TestGatherArgmax(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float64,
+		[]int{3, 2},
+		tensor.WithBacking([]float64{1, 2, 4, 3, 5, 6}),
+	)
+	target := tensor.NewDense(
+		tensor.Float64,
+		[]int{3, 1},
+		tensor.WithBacking([]float64{2, 4, 6}),
+	)
+
+	indices, err := Argmax(in, 1, true, TieFirst)
+	if err != nil {
+		t.Error(err)
+	}
+	out, err := Gather(in, 1, indices)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+}
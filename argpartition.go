@@ -0,0 +1,179 @@
+package top
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"gorgonia.org/tensor"
+)
+
+// insertionThreshold is the size of partitions below which introselect
+// uses an insertion sort
+const insertionThreshold int = 12
+
+// Argpartition returns an int tensor containing indices that would
+// partition t along axis around its kth element. Along each row of
+// the returned indices, the index at position kth is the index of the
+// element that would be at position kth if the row were sorted. All
+// indices before position kth refer to elements no larger than this
+// element, and all indices after position kth refer to elements no
+// smaller than this element. The order of the indices within the two
+// partitions is undefined.
+//
+// Each row is partitioned with introselect, which runs in linear time
+// on average and O(n log n) time in the worst case, rather than fully
+// sorting the row as Argsort does.
+//
+// Argpartition works on tensors of type float64, float32, or any int
+// type.
+//
+// See NumPy's documentation for more details and usage:
+// https://numpy.org/doc/stable/reference/generated/numpy.argpartition.html
+func Argpartition(t tensor.Tensor, kth, axis int) (tensor.Tensor, error) {
+	shape := t.Shape()
+	if axis < 0 || axis >= len(shape) {
+		return nil, fmt.Errorf("argpartition: axis out of range [%v] for "+
+			"tensor with %v dimensions", axis, len(shape))
+	}
+	if kth < 0 || kth >= shape[axis] {
+		return nil, fmt.Errorf("argpartition: kth out of range [%v] for "+
+			"axis with size %v", kth, shape[axis])
+	}
+
+	starts, stride := axisRows(shape, axis)
+	partitioned := make([]int, shape.TotalSize())
+	indices := make([]int, shape[axis])
+	for _, start := range starts {
+		for i := range indices {
+			indices[i] = start + i*stride
+		}
+		row, err := take(t, indices)
+		if err != nil {
+			return nil, fmt.Errorf("argpartition: %v", err)
+		}
+
+		a := newArgSorter(sortable(row))
+		introselect(a, kth)
+		for i, arg := range a.ind {
+			partitioned[start+i*stride] = arg
+		}
+	}
+
+	return newTensor(shape.Clone(), partitioned), nil
+}
+
+// sortable returns a sort.Interface for a []float64, []float32, or
+// []int slice
+func sortable(data interface{}) sort.Interface {
+	switch d := data.(type) {
+	case []float64:
+		return sort.Float64Slice(d)
+	case []float32:
+		return float32Slice(d)
+	case []int:
+		return sort.IntSlice(d)
+	default:
+		panic(fmt.Sprintf("sortable: unknown slice type %T", data))
+	}
+}
+
+// introselect reorders data such that the element at position k is the
+// element that would be at position k if data were sorted, with no
+// larger elements before it and no smaller elements after it.
+//
+// Quickselect with median-of-three pivots is used until the recursion
+// depth exceeds a limit proportional to the logarithm of the size of
+// data, after which the remaining partition is heapsorted. This
+// bounds the worst-case running time to O(n log n).
+func introselect(data sort.Interface, k int) {
+	lo, hi := 0, data.Len()-1
+	depth := 2 * bits.Len(uint(data.Len()))
+
+	for hi-lo >= insertionThreshold {
+		if depth == 0 {
+			heapSort(data, lo, hi+1)
+			return
+		}
+		depth--
+
+		p := partition(data, lo, hi)
+		switch {
+		case k == p:
+			return
+		case k < p:
+			hi = p - 1
+		default:
+			lo = p + 1
+		}
+	}
+
+	insertionSort(data, lo, hi+1)
+}
+
+// partition partitions data[lo:hi+1] around a median-of-three pivot,
+// returning the final position of the pivot
+func partition(data sort.Interface, lo, hi int) int {
+	// Move the median of data[lo], data[mid], and data[hi] to data[hi]
+	mid := lo + (hi-lo)/2
+	if data.Less(mid, lo) {
+		data.Swap(mid, lo)
+	}
+	if data.Less(hi, lo) {
+		data.Swap(hi, lo)
+	}
+	if data.Less(mid, hi) {
+		data.Swap(mid, hi)
+	}
+
+	store := lo
+	for i := lo; i < hi; i++ {
+		if data.Less(i, hi) {
+			data.Swap(i, store)
+			store++
+		}
+	}
+	data.Swap(store, hi)
+	return store
+}
+
+// insertionSort sorts data[a:b] using insertion sort
+func insertionSort(data sort.Interface, a, b int) {
+	for i := a + 1; i < b; i++ {
+		for j := i; j > a && data.Less(j, j-1); j-- {
+			data.Swap(j, j-1)
+		}
+	}
+}
+
+// heapSort sorts data[a:b] using heapsort
+func heapSort(data sort.Interface, a, b int) {
+	n := b - a
+	for i := (n - 1) / 2; i >= 0; i-- {
+		siftDown(data, i, n, a)
+	}
+	for i := n - 1; i >= 0; i-- {
+		data.Swap(a, a+i)
+		siftDown(data, 0, i, a)
+	}
+}
+
+// siftDown implements the heap property on data[lo:hi], where offset
+// is the index of the root of the heap in data
+func siftDown(data sort.Interface, lo, hi, offset int) {
+	root := lo
+	for {
+		child := 2*root + 1
+		if child >= hi {
+			return
+		}
+		if child+1 < hi && data.Less(offset+child, offset+child+1) {
+			child++
+		}
+		if !data.Less(offset+root, offset+child) {
+			return
+		}
+		data.Swap(offset+root, offset+child)
+		root = child
+	}
+}
@@ -0,0 +1,102 @@
+package top
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"gorgonia.org/tensor"
+)
+
+// TestArgpartition tests Argpartition on randomly generated tensors by
+// checking that the kth element is in its sorted position and that
+// the elements are correctly partitioned around it
+func TestArgpartition(t *testing.T) {
+	const numTests int = 30
+	const sizeMin int = 1
+	const sizeMax int = 200
+	rand.Seed(time.Now().UnixNano())
+
+	for i := 0; i < numTests; i++ {
+		rows := randInt(1, 1, 4)[0]
+		cols := randInt(1, sizeMin, sizeMax)[0]
+		kth := rand.Intn(cols)
+
+		// Use few distinct values in half of the tests to exercise
+		// partitions with many equal elements
+		distinct := 1000
+		if i%2 == 0 {
+			distinct = 3
+		}
+
+		inBacking := make([]float64, rows*cols)
+		for j := range inBacking {
+			inBacking[j] = float64(rand.Intn(distinct))
+		}
+		in := tensor.NewDense(
+			tensor.Float64,
+			[]int{rows, cols},
+			tensor.WithBacking(inBacking),
+		)
+
+		out, err := Argpartition(in, kth, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		indices := out.Data().([]int)
+
+		for r := 0; r < rows; r++ {
+			row := inBacking[r*cols : (r+1)*cols]
+			sorted := append([]float64(nil), row...)
+			sort.Float64s(sorted)
+
+			// Ensure the indices are a permutation of the row
+			seen := make([]bool, cols)
+			for _, index := range indices[r*cols : (r+1)*cols] {
+				if seen[index] {
+					t.Fatalf("index %v returned multiple times", index)
+				}
+				seen[index] = true
+			}
+
+			pivot := row[indices[r*cols+kth]]
+			if pivot != sorted[kth] {
+				t.Errorf("expected kth element %v but got %v", sorted[kth],
+					pivot)
+			}
+			for j := 0; j < cols; j++ {
+				value := row[indices[r*cols+j]]
+				if (j < kth && value > pivot) || (j > kth && value < pivot) {
+					t.Errorf("element %v at position %v is not partitioned "+
+						"around %v at position %v", value, j, pivot, kth)
+				}
+			}
+		}
+	}
+}
+
+func TestArgpartitionAxis(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Int8,
+		[]int{3, 2},
+		tensor.WithBacking([]int8{5, 0, 1, 2, 3, 1}),
+	)
+	target := tensor.NewDense(
+		tensor.Int,
+		[]int{3, 2},
+		tensor.WithBacking([]int{1, 0, 2, 2, 0, 1}),
+	)
+
+	out, err := Argpartition(in, 1, 0)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	if _, err := Argpartition(in, 3, 0); err == nil {
+		t.Error("expected error for illegal kth")
+	}
+}
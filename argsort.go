@@ -8,67 +8,93 @@ import (
 )
 
 // Argsort returns an int tensor containing indices that would sort
-// t along axis.
+// t along axis. The sort is stable.
+//
+// Argsort works on tensors of type float64, float32, bool, or any int
+// type. Integer types are sorted using their own type, without being
+// converted to int, and false is sorted before true.
 func Argsort(t tensor.Tensor, axis int) (tensor.Tensor, error) {
 	// Ensure valid data type of tensor
 	switch t.Data().(type) {
-	case []float64, []float32, []int:
+	case []float64, []float32, []int, []int8, []int16, []int32, []int64,
+		[]uint, []uint8, []uint16, []uint32, []uint64, []bool:
 
 	default:
 		return nil, fmt.Errorf("argsort: unknown tensor type %v", t.Dtype())
 	}
 
-	if axis >= len(t.Shape()) {
+	if axis < 0 || axis >= len(t.Shape()) {
 		return nil, fmt.Errorf("argsort: axis out of range [%v] for "+
 			"tensor with %v dimensions", axis, len(t.Shape()))
 	}
 
+	indices, err := argsortRows(t, axis, sortRow)
+	if err != nil {
+		return nil, fmt.Errorf("argsort: %v", err)
+	}
+	return indices, nil
+}
+
+// rowSorter argsorts a specific row of data along axis, sending the
+// indices to update in the backing slice of the argsort'd tensor along
+// backingInd, and the arguments that sort the tensor along sortedInd.
+// Any errors during the computation are sent along errors. See sortRow
+// for more details.
+type rowSorter func(data tensor.Tensor, backingInd, sortedInd chan []int,
+	row, axis int, errors chan error)
+
+// argsortRows returns an int tensor containing indices that would sort
+// t along axis, where each row of t along axis is argsort'd
+// concurrently by sorter.
+func argsortRows(t tensor.Tensor, axis int, sorter rowSorter) (tensor.Tensor,
+	error) {
 	shape := make([]int, len(t.Shape()))
 	copy(shape, t.Shape())
 	reps := tensor.ProdInts(append(shape[:axis], shape[axis+1:]...))
 
-	// sortedInd is the channel along which the argsort'd indices for a
-	// specific row will be sent
-	sortedInd := make(chan []int, reps)
+	// sortedInd[i] is the channel along which the argsort'd indices for
+	// row i will be sent
+	sortedInd := make([]chan []int, reps)
 
-	// backingInd is the channel along which the indices in the
+	// backingInd[i] is the channel along which the indices in the
 	// backing slice of the final tensor at which data from
-	// sortedInd should be placed is sent. That is, if `b` is sent along
-	// backingInd and `s` along sortedInd, then `s[i]` should be placed
-	// at index `b[i]` in the backing data of the final, argsort'd
-	// tensor
-	backingInd := make(chan []int, reps)
+	// sortedInd[i] should be placed is sent. That is, if `b` is sent
+	// along backingInd[i] and `s` along sortedInd[i], then `s[j]` should
+	// be placed at index `b[j]` in the backing data of the final,
+	// argsort'd tensor
+	backingInd := make([]chan []int, reps)
 
-	// errors is the channel along which any errors in concurrent
-	// argsort'ng are sent
-	errors := make(chan error, reps)
+	// errors[i] is the channel along which any errors in concurrently
+	// argsort'ng row i are sent
+	errors := make([]chan error, reps)
 
 	// Sort each row concurrently passing back a slice of indices that
 	// would sort the input tensor along the row, along with the indices
 	// at which these values should be set for the backing data of
-	// the argsort'd tensor
+	// the argsort'd tensor. Each row uses its own channels so that the
+	// results of different rows cannot be interleaved.
 	for i := 0; i < reps; i++ {
-		row := i // Since i may change before the goroutine needs it
-		go sortRow(t, backingInd, sortedInd, row, axis, errors)
+		sortedInd[i] = make(chan []int, 1)
+		backingInd[i] = make(chan []int, 1)
+		errors[i] = make(chan error, 1)
+
+		go sorter(t, backingInd[i], sortedInd[i], i, axis, errors[i])
 	}
 
 	// Set each row based on the concurrent argsorts
 	sorted := make([]int, t.Size()) // Backing for argsort'd tensor
 	for k := 0; k < reps; k++ {
-		indices := <-backingInd // Indices to set in the backing slice
-		args := <-sortedInd     // Sorted indices of the input slice
-		err := <-errors         // Errors during sorting
+		err := <-errors[k] // Errors during sorting
 		if err != nil {
-			return nil, fmt.Errorf("argsort: %v", err)
+			return nil, err
 		}
+		indices := <-backingInd[k] // Indices to set in the backing slice
+		args := <-sortedInd[k]     // Sorted indices of the input slice
 
 		for i := 0; i < len(indices); i++ {
 			sorted[indices[i]] = args[i]
 		}
 	}
-	close(backingInd)
-	close(sortedInd)
-	close(errors)
 
 	// Construct and returns the argsort'd tensor
 	indices := tensor.NewDense(
@@ -111,8 +137,16 @@ func (a *argSorter) Swap(i, j int) {
 	a.ind[i], a.ind[j] = a.ind[j], a.ind[i]
 }
 
-// argSort returns the indices that would sort s
+// argSort returns the indices that would stably sort s. For large
+// slices of the types used by Argsort, a radix sort is used, otherwise
+// a comparison sort is used. Both result in identical indices.
 func argSort(s sort.Interface) []int {
+	if s.Len() >= radixThreshold {
+		if keys, bytes, ok := radixKeys(s); ok {
+			return radixArgsort(keys, bytes)
+		}
+	}
+
 	a := newArgSorter(s)
 	sort.Stable(a)
 
@@ -141,6 +175,10 @@ func sortRow(data tensor.Tensor, backingInd, sortedInd chan []int, row,
 	case []int:
 		intSortRow(data, backingInd, sortedInd, row, axis, errors)
 
+	case []int8, []int16, []int32, []int64, []uint, []uint8, []uint16,
+		[]uint32, []uint64, []bool:
+		typedSortRow(data, backingInd, sortedInd, row, axis, errors)
+
 	default:
 		errors <- fmt.Errorf("sortRow: unknown tensor type %v", data.Dtype())
 	}
@@ -360,6 +398,167 @@ func intSortRow(data tensor.Tensor, backingInd, sortedInd chan []int, row,
 	errors <- nil
 }
 
+// typedSortRow sorts a row of a tensor, where data is the backing slice
+// of the tensor. The tensor may store any integer type other than int,
+// or bools. See sortRow.
+func typedSortRow(data tensor.Tensor, backingInd, sortedInd chan []int, row,
+	axis int, errors chan error) {
+	indices, err := rowBackingIndices(data, row, axis)
+	if err != nil {
+		errors <- fmt.Errorf("typedSortRow: %v", err)
+
+		backingInd <- nil
+		sortedInd <- nil
+		return
+	}
+
+	// Construct the current row with the type stored by the tensor
+	currentRow := sortableRow(data.Data(), indices)
+	if currentRow == nil {
+		errors <- fmt.Errorf("typedSortRow: unknown tensor type %v",
+			data.Dtype())
+
+		backingInd <- nil
+		sortedInd <- nil
+		return
+	}
+
+	// Argsort this row only and send the argsort'd indices, along with
+	// the indices at which to place them in the backing slice of the
+	// final tensor to the main goroutine
+	args := argSort(currentRow)
+
+	sortedInd <- args
+	backingInd <- indices
+	errors <- nil
+}
+
+// sortableRow returns a sort.Interface holding the elements of the
+// backing slice data at indices, keeping the type stored by data. If
+// data is not a slice of a type supported by Argsort, nil is returned.
+func sortableRow(data interface{}, indices []int) sort.Interface {
+	switch d := data.(type) {
+	case []float64:
+		r := make(sort.Float64Slice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []float32:
+		r := make(float32Slice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []int:
+		r := make(sort.IntSlice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []int8:
+		r := make(int8Slice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []int16:
+		r := make(int16Slice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []int32:
+		r := make(int32Slice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []int64:
+		r := make(int64Slice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []uint:
+		r := make(uintSlice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []uint8:
+		r := make(uint8Slice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []uint16:
+		r := make(uint16Slice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []uint32:
+		r := make(uint32Slice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []uint64:
+		r := make(uint64Slice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	case []bool:
+		r := make(boolSlice, len(indices))
+		for i, j := range indices {
+			r[i] = d[j]
+		}
+		return r
+
+	default:
+		return nil
+	}
+}
+
+// rowBackingIndices returns the indices into the backing slice of data
+// of each element of row along axis. See sortRow.
+func rowBackingIndices(data tensor.Tensor, row, axis int) ([]int, error) {
+	static, err := getStaticRowIndices(data, row, axis)
+	if err != nil {
+		return nil, fmt.Errorf("rowBackingIndices: %v", err)
+	}
+
+	dimSize := data.Shape()[axis]
+	indices := make([]int, 0, dimSize)
+	for i := 0; i < dimSize; i++ {
+		// Set the index of the next element along the current axis
+		static[axis] = i
+
+		// Get the index into the backing slice of the tensor
+		j, err := tensor.Ltoi(data.Shape(), data.Strides(), static...)
+		if err != nil {
+			return nil, fmt.Errorf("rowBackingIndices: could not compute "+
+				"index of coordinates %v into backing slice", static)
+		}
+		indices = append(indices, j)
+	}
+	return indices, nil
+}
+
 // float32Slice is a []float32 wrapper to implement sort.Interface
 type float32Slice []float32
 
@@ -371,3 +570,124 @@ func (s float32Slice) Less(i, j int) bool { return s[i] < s[j] }
 
 // Swap implements the interface sort.Interface
 func (s float32Slice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// int8Slice is a []int8 wrapper to implement sort.Interface
+type int8Slice []int8
+
+// Len implements the interface sort.Interface
+func (s int8Slice) Len() int { return len(s) }
+
+// Less implements the interface sort.Interface
+func (s int8Slice) Less(i, j int) bool { return s[i] < s[j] }
+
+// Swap implements the interface sort.Interface
+func (s int8Slice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// int16Slice is a []int16 wrapper to implement sort.Interface
+type int16Slice []int16
+
+// Len implements the interface sort.Interface
+func (s int16Slice) Len() int { return len(s) }
+
+// Less implements the interface sort.Interface
+func (s int16Slice) Less(i, j int) bool { return s[i] < s[j] }
+
+// Swap implements the interface sort.Interface
+func (s int16Slice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// int32Slice is a []int32 wrapper to implement sort.Interface
+type int32Slice []int32
+
+// Len implements the interface sort.Interface
+func (s int32Slice) Len() int { return len(s) }
+
+// Less implements the interface sort.Interface
+func (s int32Slice) Less(i, j int) bool { return s[i] < s[j] }
+
+// Swap implements the interface sort.Interface
+func (s int32Slice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// int64Slice is a []int64 wrapper to implement sort.Interface
+type int64Slice []int64
+
+// Len implements the interface sort.Interface
+func (s int64Slice) Len() int { return len(s) }
+
+// Less implements the interface sort.Interface
+func (s int64Slice) Less(i, j int) bool { return s[i] < s[j] }
+
+// Swap implements the interface sort.Interface
+func (s int64Slice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// uintSlice is a []uint wrapper to implement sort.Interface
+type uintSlice []uint
+
+// Len implements the interface sort.Interface
+func (s uintSlice) Len() int { return len(s) }
+
+// Less implements the interface sort.Interface
+func (s uintSlice) Less(i, j int) bool { return s[i] < s[j] }
+
+// Swap implements the interface sort.Interface
+func (s uintSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// uint8Slice is a []uint8 wrapper to implement sort.Interface
+type uint8Slice []uint8
+
+// Len implements the interface sort.Interface
+func (s uint8Slice) Len() int { return len(s) }
+
+// Less implements the interface sort.Interface
+func (s uint8Slice) Less(i, j int) bool { return s[i] < s[j] }
+
+// Swap implements the interface sort.Interface
+func (s uint8Slice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// uint16Slice is a []uint16 wrapper to implement sort.Interface
+type uint16Slice []uint16
+
+// Len implements the interface sort.Interface
+func (s uint16Slice) Len() int { return len(s) }
+
+// Less implements the interface sort.Interface
+func (s uint16Slice) Less(i, j int) bool { return s[i] < s[j] }
+
+// Swap implements the interface sort.Interface
+func (s uint16Slice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// uint32Slice is a []uint32 wrapper to implement sort.Interface
+type uint32Slice []uint32
+
+// Len implements the interface sort.Interface
+func (s uint32Slice) Len() int { return len(s) }
+
+// Less implements the interface sort.Interface
+func (s uint32Slice) Less(i, j int) bool { return s[i] < s[j] }
+
+// Swap implements the interface sort.Interface
+func (s uint32Slice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// uint64Slice is a []uint64 wrapper to implement sort.Interface
+type uint64Slice []uint64
+
+// Len implements the interface sort.Interface
+func (s uint64Slice) Len() int { return len(s) }
+
+// Less implements the interface sort.Interface
+func (s uint64Slice) Less(i, j int) bool { return s[i] < s[j] }
+
+// Swap implements the interface sort.Interface
+func (s uint64Slice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// boolSlice is a []bool wrapper to implement sort.Interface, where
+// false is ordered before true
+type boolSlice []bool
+
+// Len implements the interface sort.Interface
+func (s boolSlice) Len() int { return len(s) }
+
+// Less implements the interface sort.Interface
+func (s boolSlice) Less(i, j int) bool { return !s[i] && s[j] }
+
+// Swap implements the interface sort.Interface
+func (s boolSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
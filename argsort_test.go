@@ -2,6 +2,7 @@ package top
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"gorgonia.org/tensor"
@@ -25,14 +26,14 @@ func ExampleArgsort() {
 
 	// Output:
 	// Input data:
-	//   ⎡1  5  0⎤
-	//   ⎢3  9  8⎥
-	//   ⎣4  6  7⎦
-
+	//  ⎡1  5  0⎤
+	// ⎢3  9  8⎥
+	// ⎣4  6  7⎦
+	//
 	// Indices to sort input along axis columns:
-	//   ⎡2  0  1⎤
-	//   ⎢0  2  1⎥
-	//   ⎣0  1  2⎦
+	//  ⎡2  0  1⎤
+	// ⎢0  2  1⎥
+	// ⎣0  1  2⎦
 }
 
 func TestArgsort3D(t *testing.T) {
@@ -168,3 +169,64 @@ func TestArgsort(t *testing.T) {
 	}
 
 }
+
+// TestArgsortTypes tests Argsort on tensors of each integer type and
+// bool, ensuring that no conversion to int takes place
+func TestArgsortTypes(t *testing.T) {
+	ins := []*tensor.Dense{
+		tensor.NewDense(tensor.Int8, []int{2, 3},
+			tensor.WithBacking([]int8{3, -128, 1, 127, 0, -1})),
+		tensor.NewDense(tensor.Int16, []int{2, 3},
+			tensor.WithBacking([]int16{3, -32768, 1, 32767, 0, -1})),
+		tensor.NewDense(tensor.Int32, []int{2, 3},
+			tensor.WithBacking([]int32{3, math.MinInt32, 1, math.MaxInt32, 0,
+				-1})),
+		tensor.NewDense(tensor.Int64, []int{2, 3},
+			tensor.WithBacking([]int64{3, math.MinInt64, 1, math.MaxInt64, 0,
+				-1})),
+		tensor.NewDense(tensor.Uint, []int{2, 3},
+			tensor.WithBacking([]uint{3, 0, 1, math.MaxUint, 1, 0})),
+		tensor.NewDense(tensor.Uint8, []int{2, 3},
+			tensor.WithBacking([]uint8{3, 0, 1, 255, 1, 0})),
+		tensor.NewDense(tensor.Uint16, []int{2, 3},
+			tensor.WithBacking([]uint16{3, 0, 1, 65535, 1, 0})),
+		tensor.NewDense(tensor.Uint32, []int{2, 3},
+			tensor.WithBacking([]uint32{3, 0, 1, math.MaxUint32, 1, 0})),
+		tensor.NewDense(tensor.Uint64, []int{2, 3},
+			tensor.WithBacking([]uint64{3, 0, 1, math.MaxUint64, 1, 0})),
+	}
+	target := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 3},
+		tensor.WithBacking([]int{1, 2, 0, 2, 1, 0}),
+	)
+
+	for _, in := range ins {
+		out, err := Argsort(in, 1)
+		if err != nil {
+			t.Error(err)
+		}
+		if !out.Eq(target) {
+			t.Errorf("%v: expected: \n%v \nreceived: \n%v", in.Dtype(),
+				target, out)
+		}
+	}
+
+	boolIn := tensor.NewDense(
+		tensor.Bool,
+		[]int{5},
+		tensor.WithBacking([]bool{true, false, true, false, false}),
+	)
+	boolTarget := tensor.NewDense(
+		tensor.Int,
+		[]int{5},
+		tensor.WithBacking([]int{1, 3, 4, 0, 2}),
+	)
+	out, err := Argsort(boolIn, 0)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(boolTarget) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", boolTarget, out)
+	}
+}
@@ -0,0 +1,93 @@
+package top
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorgonia.org/tensor"
+)
+
+// ArgsortFunc returns an int tensor containing indices that would sort
+// t along axis, where elements are ordered by less. The less function
+// is called with two elements of t and should report whether the
+// first element should be sorted before the second. For example, to
+// sort a float64 tensor by absolute value:
+//
+//	less := func(a, b interface{}) bool {
+//		return math.Abs(a.(float64)) < math.Abs(b.(float64))
+//	}
+//
+// The sort is stable. Unlike Argsort, ArgsortFunc works on tensors of
+// any data type, and elements are passed to less with the type stored
+// by t (e.g. bool, uint16, ...) without any conversion.
+func ArgsortFunc(t tensor.Tensor, axis int,
+	less func(a, b interface{}) bool) (tensor.Tensor, error) {
+	if less == nil {
+		return nil, fmt.Errorf("argsortFunc: less cannot be nil")
+	}
+
+	if axis < 0 || axis >= len(t.Shape()) {
+		return nil, fmt.Errorf("argsortFunc: axis out of range [%v] for "+
+			"tensor with %v dimensions", axis, len(t.Shape()))
+	}
+
+	if reflect.TypeOf(t.Data()).Kind() != reflect.Slice {
+		return nil, fmt.Errorf("argsortFunc: tensor data of type %T is not "+
+			"a slice", t.Data())
+	}
+
+	indices, err := argsortRows(t, axis, funcSortRow(less))
+	if err != nil {
+		return nil, fmt.Errorf("argsortFunc: %v", err)
+	}
+	return indices, nil
+}
+
+// funcSortRow returns a rowSorter which sorts a row of a tensor of any
+// type using less to compare elements. See sortRow.
+func funcSortRow(less func(a, b interface{}) bool) rowSorter {
+	return func(data tensor.Tensor, backingInd, sortedInd chan []int, row,
+		axis int, errors chan error) {
+		indices, err := rowBackingIndices(data, row, axis)
+		if err != nil {
+			errors <- fmt.Errorf("funcSortRow: %v", err)
+
+			backingInd <- nil
+			sortedInd <- nil
+			return
+		}
+
+		// Construct the current row, keeping the type stored by the
+		// tensor
+		backing := reflect.ValueOf(data.Data())
+		currentRow := make([]interface{}, len(indices))
+		for i, j := range indices {
+			currentRow[i] = backing.Index(j).Interface()
+		}
+
+		// Argsort this row only and send the argsort'd indices, along
+		// with the indices at which to place them in the backing slice
+		// of the final tensor to the main goroutine
+		args := argSort(funcSlice{s: currentRow, less: less})
+
+		sortedInd <- args
+		backingInd <- indices
+		errors <- nil
+	}
+}
+
+// funcSlice is a []interface{} wrapper to implement sort.Interface
+// using a custom less function
+type funcSlice struct {
+	s    []interface{}
+	less func(a, b interface{}) bool
+}
+
+// Len implements the interface sort.Interface
+func (f funcSlice) Len() int { return len(f.s) }
+
+// Less implements the interface sort.Interface
+func (f funcSlice) Less(i, j int) bool { return f.less(f.s[i], f.s[j]) }
+
+// Swap implements the interface sort.Interface
+func (f funcSlice) Swap(i, j int) { f.s[i], f.s[j] = f.s[j], f.s[i] }
@@ -0,0 +1,103 @@
+package top
+
+import (
+	"math"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestArgsortFunc(t *testing.T) {
+	// Sort by absolute value
+	in := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{-3, 1, -2, 0.5, -0.25, 4}),
+	)
+	target := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 3},
+		tensor.WithBacking([]int{1, 2, 0, 1, 0, 2}),
+	)
+
+	out, err := ArgsortFunc(in, 1, func(a, b interface{}) bool {
+		return math.Abs(a.(float64)) < math.Abs(b.(float64))
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	// Sort bools in descending order, which should be stable
+	boolIn := tensor.NewDense(
+		tensor.Bool,
+		[]int{5},
+		tensor.WithBacking([]bool{false, true, false, true, true}),
+	)
+	boolTarget := tensor.NewDense(
+		tensor.Int,
+		[]int{5},
+		tensor.WithBacking([]int{1, 3, 4, 0, 2}),
+	)
+
+	out, err = ArgsortFunc(boolIn, 0, func(a, b interface{}) bool {
+		return a.(bool) && !b.(bool)
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(boolTarget) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", boolTarget, out)
+	}
+
+	// Sort unsigned integers without conversion
+	uintIn := tensor.NewDense(
+		tensor.Uint16,
+		[]int{2, 2},
+		tensor.WithBacking([]uint16{9, 3, 1, 7}),
+	)
+	uintTarget := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 2},
+		tensor.WithBacking([]int{1, 0, 0, 1}),
+	)
+
+	out, err = ArgsortFunc(uintIn, 0, func(a, b interface{}) bool {
+		return a.(uint16) < b.(uint16)
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(uintTarget) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", uintTarget, out)
+	}
+}
+
+// TestArgsortFuncArgsort tests if ArgsortFunc with the natural ordering
+// is equivalent to Argsort
+func TestArgsortFuncArgsort(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float32,
+		[]int{3, 4},
+		tensor.WithBacking([]float32{3, 1, 2, 1, 0, 5, 5, 2, 8, 1, 0, 9}),
+	)
+
+	for axis := 0; axis < 2; axis++ {
+		target, err := Argsort(in, axis)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := ArgsortFunc(in, axis, func(a, b interface{}) bool {
+			return a.(float32) < b.(float32)
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		if !out.Eq(target) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+		}
+	}
+}
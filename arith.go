@@ -0,0 +1,205 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// broadcastApply broadcasts the argument tensors against each other
+// following the NumPy broadcasting rules and applies f to each
+// position of the broadcast tensors. The argument to f holds the
+// element of each tensor at that position, in the order in which the
+// tensors were given. The tensors must all have the same floating point
+// type. The results are returned in row-major order together with the
+// broadcast shape.
+func broadcastApply(f func(v []float64) float64,
+	ts ...tensor.Tensor) ([]float64, tensor.Shape, error) {
+	shapes := make([]tensor.Shape, len(ts))
+	data := make([][]float64, len(ts))
+	for i := range ts {
+		if ts[i].Dtype() != ts[0].Dtype() {
+			return nil, nil, fmt.Errorf("broadcastApply: tensors must have "+
+				"the same type but got %v and %v", ts[0].Dtype(),
+				ts[i].Dtype())
+		}
+
+		var err error
+		if data[i], err = toFloat64Data(ts[i]); err != nil {
+			return nil, nil, fmt.Errorf("broadcastApply: %v", err)
+		}
+		shapes[i] = ts[i].Shape()
+	}
+
+	shape, err := broadcastShape(shapes...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("broadcastApply: %v", err)
+	}
+	indices := make([][]int, len(ts))
+	for i := range ts {
+		indices[i] = broadcastIndices(shapes[i], shape)
+	}
+
+	out := make([]float64, shape.TotalSize())
+	v := make([]float64, len(ts))
+	for i := range out {
+		for j := range ts {
+			v[j] = data[j][indices[j][i]]
+		}
+		out[i] = f(v)
+	}
+	return out, shape, nil
+}
+
+// elementwise applies f elementwise to the broadcast of the argument
+// floating point tensors, returning a tensor of the same type as the
+// arguments. See broadcastApply.
+func elementwise(f func(v []float64) float64,
+	ts ...tensor.Tensor) (tensor.Tensor, error) {
+	out, shape, err := broadcastApply(f, ts...)
+	if err != nil {
+		return nil, err
+	}
+	return fromFloat64Data(ts[0].Dtype(), shape, out), nil
+}
+
+// unbroadcast sums a gradient of the argument data and shape over the
+// dimensions along which a tensor of shape shape was broadcast, so that
+// the returned gradient has shape shape. This is the backward pass of
+// broadcasting.
+func unbroadcast(dt tensor.Dtype, data []float64, dataShape,
+	shape tensor.Shape) tensor.Tensor {
+	out := make([]float64, shape.TotalSize())
+	for i, j := range broadcastIndices(shape, dataShape) {
+		out[j] += data[i]
+	}
+	return fromFloat64Data(dt, shape.Clone(), out)
+}
+
+// full returns a tensor of the argument floating point type and shape
+// with each element set to value
+func full(dt tensor.Dtype, shape tensor.Shape, value float64) tensor.Tensor {
+	data := make([]float64, shape.TotalSize())
+	for i := range data {
+		data[i] = value
+	}
+	return fromFloat64Data(dt, shape.Clone(), data)
+}
+
+// zerosLike returns a tensor of zeros with the same floating point type
+// and shape as t
+func zerosLike(t tensor.Tensor) tensor.Tensor {
+	return full(t.Dtype(), t.Shape(), 0)
+}
+
+// orZeros returns t if it is non-nil, and a tensor of zeros with the
+// same type and shape as like otherwise. This is used to treat nil
+// tangents as zeros.
+func orZeros(t, like tensor.Tensor) tensor.Tensor {
+	if t == nil {
+		return zerosLike(like)
+	}
+	return t
+}
+
+// sumAlong sums the floating point tensor t along axis. If axis is
+// NoAxis, all elements of t are summed. If keepdims is true, the
+// summed dimensions are kept with size 1. If mean is true, the mean
+// is computed instead of the sum.
+func sumAlong(t tensor.Tensor, axis int, keepdims,
+	mean bool) (tensor.Tensor, error) {
+	data, err := toFloat64Data(t)
+	if err != nil {
+		return nil, fmt.Errorf("sumAlong: %v", err)
+	}
+
+	if axis == NoAxis {
+		total := 0.0
+		for _, v := range data {
+			total += v
+		}
+		if mean {
+			total /= float64(len(data))
+		}
+
+		shape := tensor.ScalarShape()
+		if keepdims {
+			shape = make(tensor.Shape, len(t.Shape()))
+			for i := range shape {
+				shape[i] = 1
+			}
+		}
+		return fromFloat64Data(t.Dtype(), shape, []float64{total}), nil
+	}
+
+	if axis < 0 || axis >= len(t.Shape()) {
+		return nil, fmt.Errorf("sumAlong: axis out of range [%v] for "+
+			"tensor with %v dimensions", axis, len(t.Shape()))
+	}
+
+	starts, stride := axisRows(t.Shape(), axis)
+	n := t.Shape()[axis]
+	out := make([]float64, len(starts))
+	for r, start := range starts {
+		for j := 0; j < n; j++ {
+			out[r] += data[start+j*stride]
+		}
+		if mean {
+			out[r] /= float64(n)
+		}
+	}
+	shape := reducedShape(t.Shape(), axis, keepdims)
+	return fromFloat64Data(t.Dtype(), shape, out), nil
+}
+
+// sumAlongB is the backward pass of sumAlong. Given the gradient grad
+// with respect to the output of sumAlong, it returns the gradient with
+// respect to the input of sumAlong, which had the argument shape. The
+// grad tensor may have the shape resulting from calling sumAlong with
+// keepdims either true or false.
+func sumAlongB(grad tensor.Tensor, shape tensor.Shape, axis int,
+	mean bool) (tensor.Tensor, error) {
+	g, err := toFloat64Data(grad)
+	if err != nil {
+		return nil, fmt.Errorf("sumAlongB: %v", err)
+	}
+
+	out := make([]float64, shape.TotalSize())
+	if axis == NoAxis {
+		if len(g) != 1 {
+			return nil, fmt.Errorf("sumAlongB: expected grad with a single "+
+				"element but got shape %v", grad.Shape())
+		}
+		scale := 1.0
+		if mean {
+			scale = float64(len(out))
+		}
+		for i := range out {
+			out[i] = g[0] / scale
+		}
+		return fromFloat64Data(grad.Dtype(), shape.Clone(), out), nil
+	}
+
+	if axis < 0 || axis >= len(shape) {
+		return nil, fmt.Errorf("sumAlongB: axis out of range [%v] for "+
+			"tensor with %v dimensions", axis, len(shape))
+	}
+
+	starts, stride := axisRows(shape, axis)
+	if len(g) != len(starts) {
+		return nil, fmt.Errorf("sumAlongB: expected grad with %v elements "+
+			"but got shape %v", len(starts), grad.Shape())
+	}
+
+	n := shape[axis]
+	scale := 1.0
+	if mean {
+		scale = float64(n)
+	}
+	for r, start := range starts {
+		for j := 0; j < n; j++ {
+			out[start+j*stride] = g[r] / scale
+		}
+	}
+	return fromFloat64Data(grad.Dtype(), shape.Clone(), out), nil
+}
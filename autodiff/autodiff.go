@@ -0,0 +1,245 @@
+// Package autodiff implements a minimal tape-based reverse-mode
+// automatic differentiation system for tensor.Tensor values.
+//
+// A Tape records each operation applied to Variables which require
+// gradients. Calling Backward on a Variable walks the Tape in reverse,
+// computing gradients with the vector-Jacobian products registered in
+// package top, and accumulates the gradient of each Variable which
+// requires gradients. For example:
+//
+//	tape := autodiff.NewTape()
+//	x := tape.Variable(xTensor)
+//	idx := tape.Constant(actions)
+//	q, err := autodiff.Gather(x, 1, idx)
+//	...
+//	loss, err := autodiff.Mean(q, top.NoAxis, false)
+//	...
+//	err = loss.Backward()
+//	grad := x.Grad()
+//
+// Any operation registered with top.Register can be recorded using
+// Apply, so that custom operations are differentiated in the same way
+// as the builtin operations.
+package autodiff
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/samuelfneumann/top"
+	"gorgonia.org/tensor"
+)
+
+// Variable wraps a tensor.Tensor, tracking the operation which produced
+// it so that gradients can be computed with respect to it
+type Variable struct {
+	value        tensor.Tensor
+	grad         tensor.Tensor
+	requiresGrad bool
+	tape         *Tape
+}
+
+// Value returns the value of the Variable
+func (v *Variable) Value() tensor.Tensor {
+	return v.value
+}
+
+// Grad returns the gradient accumulated in the Variable by calls to
+// Backward, or nil if no gradient has been computed
+func (v *Variable) Grad() tensor.Tensor {
+	return v.grad
+}
+
+// RequiresGrad returns whether gradients are computed with respect to
+// the Variable
+func (v *Variable) RequiresGrad() bool {
+	return v.requiresGrad
+}
+
+// ZeroGrad clears the gradient of the Variable
+func (v *Variable) ZeroGrad() {
+	v.grad = nil
+}
+
+// Backward computes the gradient of the Variable with respect to every
+// Variable on its Tape which requires gradients. The Variable must hold
+// a single element, such as a scalar loss. Gradients accumulate in leaf
+// Variables over successive calls to Backward; use ZeroGrad to clear
+// them.
+func (v *Variable) Backward() error {
+	if v.value.Shape().TotalSize() != 1 {
+		return fmt.Errorf("backward: expected a variable with a single "+
+			"element but got shape %v", v.value.Shape())
+	}
+
+	return v.BackwardWithGrad(tensor.Ones(v.value.Dtype(),
+		v.value.Shape().Clone()...))
+}
+
+// BackwardWithGrad computes the gradient of <grad, v> with respect to
+// every Variable on the Tape of v which requires gradients, where grad
+// has the same shape as the value of v. See Backward for more details.
+func (v *Variable) BackwardWithGrad(grad tensor.Tensor) error {
+	if !grad.Shape().Eq(v.value.Shape()) {
+		return fmt.Errorf("backward: expected grad of shape %v but got %v",
+			v.value.Shape(), grad.Shape())
+	}
+	if !v.requiresGrad {
+		return fmt.Errorf("backward: variable does not require gradients")
+	}
+	return v.tape.backward(v, grad)
+}
+
+// record is an operation recorded on a Tape
+type record struct {
+	op     top.OpDef
+	attrs  top.Attrs
+	inputs []*Variable
+	output *Variable
+}
+
+// Tape records the operations applied to Variables so that gradients
+// can be computed by reverse-mode automatic differentiation. A Tape
+// may be used concurrently.
+type Tape struct {
+	mu      sync.Mutex
+	records []record
+}
+
+// NewTape returns a new, empty Tape
+func NewTape() *Tape {
+	return &Tape{}
+}
+
+// Variable returns a new leaf Variable on the Tape with the argument
+// value, which requires gradients
+func (t *Tape) Variable(value tensor.Tensor) *Variable {
+	return &Variable{value: value, requiresGrad: true, tape: t}
+}
+
+// Constant returns a new leaf Variable on the Tape with the argument
+// value, which does not require gradients. Constants are used for
+// inputs such as indices or targets.
+func (t *Tape) Constant(value tensor.Tensor) *Variable {
+	return &Variable{value: value, tape: t}
+}
+
+// Len returns the number of operations recorded on the Tape
+func (t *Tape) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.records)
+}
+
+// Reset removes all recorded operations from the Tape. Variables
+// created on the Tape may still be used afterwards.
+func (t *Tape) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.records = nil
+}
+
+// backward propagates grad from v back through the Tape
+func (t *Tape) backward(v *Variable, grad tensor.Tensor) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Clear the gradients of intermediate Variables left over from
+	// previous calls to Backward
+	for _, r := range t.records {
+		r.output.grad = nil
+	}
+	var err error
+	if v.grad, err = accumulate(v.grad, grad); err != nil {
+		return fmt.Errorf("backward: %v", err)
+	}
+
+	for i := len(t.records) - 1; i >= 0; i-- {
+		r := t.records[i]
+		if r.output.grad == nil {
+			continue
+		}
+
+		inputs := make([]tensor.Tensor, len(r.inputs))
+		for j, in := range r.inputs {
+			inputs[j] = in.value
+		}
+		grads, err := r.op.VJP(r.output.grad, inputs, r.output.value,
+			r.attrs)
+		if err != nil {
+			return fmt.Errorf("backward: %v: %v", r.op.Name, err)
+		}
+
+		for j, in := range r.inputs {
+			if !in.requiresGrad || j >= len(grads) || grads[j] == nil {
+				continue
+			}
+			if in.grad, err = accumulate(in.grad, grads[j]); err != nil {
+				return fmt.Errorf("backward: %v: %v", r.op.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// accumulate returns the sum of the gradients a and b, where a may be
+// nil
+func accumulate(a, b tensor.Tensor) (tensor.Tensor, error) {
+	if a == nil {
+		return b, nil
+	}
+
+	sum, err := tensor.Add(a, b)
+	if err != nil {
+		return nil, fmt.Errorf("could not accumulate gradient: %v", err)
+	}
+	return sum, nil
+}
+
+// Apply applies the registered operation called name to the inputs with
+// the argument attributes. If any input requires gradients and the
+// operation is differentiable, the operation is recorded on the Tape of
+// the inputs and the returned Variable requires gradients. All inputs
+// must belong to the same Tape.
+func Apply(name string, attrs top.Attrs, inputs ...*Variable) (*Variable,
+	error) {
+	op, ok := top.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("apply: unknown operation %q", name)
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("apply: %v: expected at least one input", name)
+	}
+
+	tape := inputs[0].tape
+	values := make([]tensor.Tensor, len(inputs))
+	requiresGrad := false
+	for i, in := range inputs {
+		if in.tape != tape {
+			return nil, fmt.Errorf("apply: %v: inputs belong to different "+
+				"tapes", name)
+		}
+		values[i] = in.value
+		requiresGrad = requiresGrad || in.requiresGrad
+	}
+
+	out, err := top.Apply(name, values, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Variable{value: out, tape: tape}
+	if requiresGrad && op.Differentiable() {
+		v.requiresGrad = true
+
+		tape.mu.Lock()
+		tape.records = append(tape.records, record{
+			op:     op,
+			attrs:  attrs,
+			inputs: inputs,
+			output: v,
+		})
+		tape.mu.Unlock()
+	}
+	return v, nil
+}
@@ -0,0 +1,223 @@
+package autodiff
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/samuelfneumann/top"
+	"gorgonia.org/tensor"
+)
+
+func TestBackward(t *testing.T) {
+	tape := NewTape()
+	x := tape.Variable(tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6}),
+	))
+	idx := tape.Constant(tensor.NewDense(
+		tensor.Int,
+		[]int{2, 1},
+		tensor.WithBacking([]int{2, 0}),
+	))
+
+	// loss = mean(gather(x, 1, idx)^2) = (x[0][2]^2 + x[1][0]^2) / 2
+	q, err := Gather(x, 1, idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sq, err := Mul(q, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loss, err := Mean(sq, top.NoAxis, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loss.Value().ScalarValue().(float64); got != 12.5 {
+		t.Errorf("expected loss 12.5 but got %v", got)
+	}
+	if tape.Len() != 3 {
+		t.Errorf("expected 3 recorded operations but got %v", tape.Len())
+	}
+
+	if err := loss.Backward(); err != nil {
+		t.Fatal(err)
+	}
+	target := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{0, 0, 3, 4, 0, 0}),
+	)
+	if !x.Grad().Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, x.Grad())
+	}
+	if idx.Grad() != nil {
+		t.Errorf("expected no gradient for constant but got %v", idx.Grad())
+	}
+
+	// Gradients accumulate over calls to Backward
+	if err := loss.Backward(); err != nil {
+		t.Fatal(err)
+	}
+	target = tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{0, 0, 6, 8, 0, 0}),
+	)
+	if !x.Grad().Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, x.Grad())
+	}
+
+	x.ZeroGrad()
+	if x.Grad() != nil {
+		t.Error("expected gradient to be cleared")
+	}
+}
+
+func TestBackwardConstants(t *testing.T) {
+	tape := NewTape()
+	a := tape.Constant(tensor.New(tensor.FromScalar(2.0)))
+	b := tape.Constant(tensor.New(tensor.FromScalar(3.0)))
+
+	c, err := Mul(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.RequiresGrad() {
+		t.Error("expected result of constants to not require gradients")
+	}
+	if tape.Len() != 0 {
+		t.Errorf("expected no recorded operations but got %v", tape.Len())
+	}
+	if err := c.Backward(); err == nil {
+		t.Error("expected error when calling Backward on a constant")
+	}
+}
+
+func TestBackwardErrors(t *testing.T) {
+	tape := NewTape()
+	x := tape.Variable(tensor.New(tensor.WithShape(2),
+		tensor.WithBacking([]float64{1, 2})))
+	y, err := Exp(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := y.Backward(); err == nil {
+		t.Error("expected error when calling Backward on a non-scalar")
+	}
+	if err := y.BackwardWithGrad(tensor.New(tensor.WithShape(3),
+		tensor.WithBacking([]float64{1, 2, 3}))); err == nil {
+		t.Error("expected error for gradient of wrong shape")
+	}
+
+	other := NewTape().Variable(tensor.New(tensor.WithShape(2),
+		tensor.WithBacking([]float64{1, 2})))
+	if _, err := Add(x, other); err == nil {
+		t.Error("expected error for variables on different tapes")
+	}
+	if _, err := Apply("unknown", nil, x); err == nil {
+		t.Error("expected error for unknown operation")
+	}
+}
+
+// TestBackwardGradCheck checks the gradients computed by the Tape for a
+// composite function against numerical gradients
+func TestBackwardGradCheck(t *testing.T) {
+	const (
+		eps = 1e-6
+		tol = 1e-5
+	)
+
+	rng := rand.New(rand.NewSource(1))
+	randTensor := func(shape ...int) tensor.Tensor {
+		data := make([]float64, tensor.ProdInts(shape))
+		for i := range data {
+			data[i] = 0.5 + rng.Float64()
+		}
+		return tensor.New(tensor.WithShape(shape...), tensor.WithBacking(data))
+	}
+	idx := tensor.NewDense(
+		tensor.Int,
+		[]int{3, 1},
+		tensor.WithBacking([]int{1, 3, 0}),
+	)
+	cond := tensor.NewDense(
+		tensor.Bool,
+		[]int{4},
+		tensor.WithBacking([]bool{true, false, true, true}),
+	)
+
+	// f computes tanh(clamp(x * y, 0.5, 1.5)) - log(x), selects elements
+	// with where, gathers along axis 1 and adds the max along axis 1
+	f := func(inputs []tensor.Tensor) ([]*Variable, *Variable, error) {
+		tape := NewTape()
+		x := tape.Variable(inputs[0])
+		y := tape.Variable(inputs[1])
+
+		xy, err := Mul(x, y)
+		if err != nil {
+			return nil, nil, err
+		}
+		c, err := Clamp(xy, 0.5, 1.5)
+		if err != nil {
+			return nil, nil, err
+		}
+		th, err := Tanh(c)
+		if err != nil {
+			return nil, nil, err
+		}
+		lx, err := Log(x)
+		if err != nil {
+			return nil, nil, err
+		}
+		d, err := Sub(th, lx)
+		if err != nil {
+			return nil, nil, err
+		}
+		w, err := Where(tape.Constant(cond), d, x)
+		if err != nil {
+			return nil, nil, err
+		}
+		g, err := Gather(w, 1, tape.Constant(idx))
+		if err != nil {
+			return nil, nil, err
+		}
+		m, err := MaxAlong(w, 1, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		out, err := Add(g, m)
+		return []*Variable{x, y}, out, err
+	}
+
+	forward := func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+		_, out, err := f(inputs)
+		if err != nil {
+			return nil, err
+		}
+		return out.Value(), nil
+	}
+	vjp := func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+		error) {
+		vars, out, err := f(inputs)
+		if err != nil {
+			return nil, err
+		}
+		if err := out.BackwardWithGrad(grad); err != nil {
+			return nil, err
+		}
+		return []tensor.Tensor{vars[0].Grad(), vars[1].Grad()}, nil
+	}
+
+	inputs := []tensor.Tensor{randTensor(3, 4), randTensor(1, 4)}
+	result, err := top.GradCheck(forward, vjp, inputs, eps,
+		rand.NewSource(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MaxAbsError > tol || math.IsNaN(result.MaxAbsError) {
+		t.Errorf("gradient check failed: %v", result)
+	}
+}
@@ -0,0 +1,111 @@
+package autodiff
+
+import (
+	"github.com/samuelfneumann/top"
+)
+
+// Add returns the elementwise sum x + y, broadcasting x and y against
+// each other
+func Add(x, y *Variable) (*Variable, error) {
+	return Apply("add", nil, x, y)
+}
+
+// Sub returns the elementwise difference x - y, broadcasting x and y
+// against each other
+func Sub(x, y *Variable) (*Variable, error) {
+	return Apply("sub", nil, x, y)
+}
+
+// Mul returns the elementwise product x * y, broadcasting x and y
+// against each other
+func Mul(x, y *Variable) (*Variable, error) {
+	return Apply("mul", nil, x, y)
+}
+
+// Div returns the elementwise quotient x / y, broadcasting x and y
+// against each other
+func Div(x, y *Variable) (*Variable, error) {
+	return Apply("div", nil, x, y)
+}
+
+// Neg returns the elementwise negation of x
+func Neg(x *Variable) (*Variable, error) {
+	return Apply("neg", nil, x)
+}
+
+// Exp returns the elementwise exponential of x
+func Exp(x *Variable) (*Variable, error) {
+	return Apply("exp", nil, x)
+}
+
+// Log returns the elementwise natural logarithm of x
+func Log(x *Variable) (*Variable, error) {
+	return Apply("log", nil, x)
+}
+
+// Tanh returns the elementwise hyperbolic tangent of x
+func Tanh(x *Variable) (*Variable, error) {
+	return Apply("tanh", nil, x)
+}
+
+// Sum sums x along axis. If axis is top.NoAxis, all elements of x are
+// summed. If keepdims is true, the summed dimensions are kept with
+// size 1.
+func Sum(x *Variable, axis int, keepdims bool) (*Variable, error) {
+	return Apply("sum", top.Attrs{"axis": axis, "keepdims": keepdims}, x)
+}
+
+// Mean computes the mean of x along axis. See Sum for more details.
+func Mean(x *Variable, axis int, keepdims bool) (*Variable, error) {
+	return Apply("mean", top.Attrs{"axis": axis, "keepdims": keepdims}, x)
+}
+
+// MaxAlong returns the maximum of x along axis. See top.MaxAlong for
+// more details.
+func MaxAlong(x *Variable, axis int, keepdims bool) (*Variable, error) {
+	return Apply("maxAlong", top.Attrs{"axis": axis, "keepdims": keepdims},
+		x)
+}
+
+// MinAlong returns the minimum of x along axis. See top.MinAlong for
+// more details.
+func MinAlong(x *Variable, axis int, keepdims bool) (*Variable, error) {
+	return Apply("minAlong", top.Attrs{"axis": axis, "keepdims": keepdims},
+		x)
+}
+
+// Gather gathers the elements of x along axis at the indices in idx,
+// which should be a Constant. See top.Gather for more details.
+func Gather(x *Variable, axis int, idx *Variable) (*Variable, error) {
+	return Apply("gather", top.Attrs{"axis": axis}, x, idx)
+}
+
+// Clamp clamps the elements of x to the interval [min, max]. See
+// top.Clamp for more details.
+func Clamp(x *Variable, min, max interface{}) (*Variable, error) {
+	return Apply("clamp", top.Attrs{"min": min, "max": max}, x)
+}
+
+// Where selects elements from x where cond is true and from y
+// otherwise. The cond Variable should hold a tensor.Bool Constant. See
+// top.Where for more details.
+func Where(cond, x, y *Variable) (*Variable, error) {
+	return Apply("where", nil, cond, x, y)
+}
+
+// Softmax computes the softmax of x along axis
+func Softmax(x *Variable, axis int) (*Variable, error) {
+	return Apply("softmax", top.Attrs{"axis": axis}, x)
+}
+
+// LogSoftmax computes the logarithm of the softmax of x along axis
+func LogSoftmax(x *Variable, axis int) (*Variable, error) {
+	return Apply("logSoftmax", top.Attrs{"axis": axis}, x)
+}
+
+// LogSumExp computes log(sum(exp(x))) along axis. If keepdims is true,
+// axis is kept with size 1.
+func LogSumExp(x *Variable, axis int, keepdims bool) (*Variable, error) {
+	return Apply("logSumExp", top.Attrs{"axis": axis, "keepdims": keepdims},
+		x)
+}
@@ -0,0 +1,85 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// broadcastShape returns the shape that results from broadcasting
+// each of shapes against each other. Broadcasting follows the NumPy
+// rules: shapes are aligned at their trailing dimensions, and two
+// dimensions are compatible if they are equal or if one of them is 1.
+// Scalar shapes broadcast against any shape.
+func broadcastShape(shapes ...tensor.Shape) (tensor.Shape, error) {
+	dims := 0
+	for _, shape := range shapes {
+		if len(shape) > dims {
+			dims = len(shape)
+		}
+	}
+
+	out := make(tensor.Shape, dims)
+	for i := range out {
+		out[i] = 1
+	}
+
+	for _, shape := range shapes {
+		lead := dims - len(shape)
+		for i, size := range shape {
+			switch {
+			case size == out[lead+i]:
+			case out[lead+i] == 1:
+				out[lead+i] = size
+			case size == 1:
+			default:
+				return nil, fmt.Errorf("broadcastShape: cannot broadcast "+
+					"shapes %v", shapes)
+			}
+		}
+	}
+	return out, nil
+}
+
+// broadcastIndices returns, for each element of a tensor of shape out
+// in row-major order, the index of the element of a contiguous tensor
+// of shape shape which is broadcast to that position. The shape
+// argument must be broadcastable to out.
+func broadcastIndices(shape, out tensor.Shape) []int {
+	// Strides into the broadcast tensor, aligned with the trailing
+	// dimensions of out. Dimensions that are broadcast have a
+	// stride of 0 so that the same element is repeatedly used.
+	strides := make([]int, len(out))
+	lead := len(out) - len(shape)
+	stride := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		if shape[i] != 1 {
+			strides[lead+i] = stride
+		}
+		stride *= shape[i]
+	}
+
+	size := out.TotalSize()
+	indices := make([]int, size)
+	if len(out) == 0 {
+		return indices
+	}
+
+	coords := make([]int, len(out))
+	index := 0
+	for i := 0; i < size; i++ {
+		indices[i] = index
+
+		// Increment the coordinates, carrying into earlier dimensions
+		for dim := len(out) - 1; dim >= 0; dim-- {
+			coords[dim]++
+			index += strides[dim]
+			if coords[dim] < out[dim] {
+				break
+			}
+			index -= coords[dim] * strides[dim]
+			coords[dim] = 0
+		}
+	}
+	return indices
+}
@@ -0,0 +1,353 @@
+package top
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gorgonia.org/tensor"
+)
+
+// Categorical is a batch of categorical distributions. The categories
+// of each distribution lie along the last axis of the logits or
+// probabilities from which the Categorical is constructed, and all
+// leading axes are batch axes. For example, logits of shape (B, K)
+// define B distributions over K categories, and the batch shape is
+// (B).
+//
+// Categorical works with float64 and float32 logits. Tensors returned
+// by its methods have the same type as the logits, except for sampled
+// categories, which are stored in tensors of type tensor.Int.
+//
+// Each method computing a quantity from the logits has a corresponding
+// backward method, suffixed with B, which returns the gradient with
+// respect to the logits.
+type Categorical struct {
+	logProbs tensor.Tensor
+	probs    tensor.Tensor
+}
+
+// NewCategorical returns a new Categorical with the argument
+// unnormalized log-probabilities along the last axis
+func NewCategorical(logits tensor.Tensor) (*Categorical, error) {
+	if len(logits.Shape()) == 0 {
+		return nil, fmt.Errorf("newCategorical: expected logits with at " +
+			"least 1 dimension but got a scalar")
+	}
+	if logits.Shape()[len(logits.Shape())-1] == 0 {
+		return nil, fmt.Errorf("newCategorical: expected at least 1 " +
+			"category")
+	}
+
+	axis := len(logits.Shape()) - 1
+	logProbs, err := LogSoftmax(logits, axis)
+	if err != nil {
+		return nil, fmt.Errorf("newCategorical: %v", err)
+	}
+	probs, err := Softmax(logits, axis)
+	if err != nil {
+		return nil, fmt.Errorf("newCategorical: %v", err)
+	}
+	return &Categorical{logProbs: logProbs, probs: probs}, nil
+}
+
+// NewCategoricalFromProbs returns a new Categorical with the argument
+// probabilities along the last axis. The probabilities must be
+// non-negative and are normalized to sum to 1 along the last axis, so
+// each distribution must have some category with non-zero probability.
+// The logits of the returned Categorical are the logarithms of the
+// probabilities, and gradients are computed with respect to these
+// logits.
+func NewCategoricalFromProbs(probs tensor.Tensor) (*Categorical, error) {
+	data, err := toFloat64Data(probs)
+	if err != nil {
+		return nil, fmt.Errorf("newCategoricalFromProbs: %v", err)
+	}
+
+	logits := make([]float64, len(data))
+	for i, p := range data {
+		if p < 0 || math.IsNaN(p) {
+			return nil, fmt.Errorf("newCategoricalFromProbs: expected "+
+				"non-negative probabilities but got %v", p)
+		}
+		logits[i] = math.Log(p)
+	}
+	if shape := probs.Shape(); len(shape) > 0 && shape[len(shape)-1] > 0 {
+		k := shape[len(shape)-1]
+		for r := 0; r < len(data)/k; r++ {
+			total := 0.0
+			for _, p := range data[r*k : (r+1)*k] {
+				total += p
+			}
+			if total == 0 {
+				return nil, fmt.Errorf("newCategoricalFromProbs: " +
+					"probabilities of a distribution sum to 0")
+			}
+		}
+	}
+
+	c, err := NewCategorical(fromFloat64Data(probs.Dtype(),
+		probs.Shape().Clone(), logits))
+	if err != nil {
+		return nil, fmt.Errorf("newCategoricalFromProbs: %v", err)
+	}
+	return c, nil
+}
+
+// Probs returns the probabilities of each category. The returned
+// tensor should not be modified.
+func (c *Categorical) Probs() tensor.Tensor {
+	return c.probs
+}
+
+// LogProbs returns the normalized log-probabilities of each category.
+// The returned tensor should not be modified.
+func (c *Categorical) LogProbs() tensor.Tensor {
+	return c.logProbs
+}
+
+// BatchShape returns the shape of the batch of distributions, which is
+// the shape of the logits without the last axis
+func (c *Categorical) BatchShape() tensor.Shape {
+	shape := c.logProbs.Shape()
+	return shape[:len(shape)-1].Clone()
+}
+
+// categories returns the number of categories of each distribution
+func (c *Categorical) categories() int {
+	shape := c.logProbs.Shape()
+	return shape[len(shape)-1]
+}
+
+// Sample draws a category from each distribution using src as the
+// source of randomness. The returned tensor has the batch shape of the
+// Categorical and is of type tensor.Int. See Multinomial for more
+// details.
+func (c *Categorical) Sample(src rand.Source) (tensor.Tensor, error) {
+	samples, err := Multinomial(c.probs, 1, true, src)
+	if err != nil {
+		return nil, fmt.Errorf("sample: %v", err)
+	}
+	return reshape(samples, c.BatchShape())
+}
+
+// LogProb returns the log-probability of each category in actions,
+// which must have the batch shape of the Categorical and store any
+// integer type. The log-probabilities are gathered along the last axis
+// of the log-probabilities of the Categorical.
+func (c *Categorical) LogProb(actions tensor.Tensor) (tensor.Tensor, error) {
+	idx, err := c.actionIndices(actions)
+	if err != nil {
+		return nil, fmt.Errorf("logProb: %v", err)
+	}
+
+	axis := len(c.logProbs.Shape()) - 1
+	out, err := Gather(c.logProbs, axis, idx)
+	if err != nil {
+		return nil, fmt.Errorf("logProb: %v", err)
+	}
+	return reshape(out, c.BatchShape())
+}
+
+// LogProbB is the backward pass of LogProb. Given the gradient grad
+// with respect to the output of LogProb, which has the batch shape of
+// the Categorical, and the actions passed to LogProb, LogProbB returns
+// the gradient with respect to the logits:
+//
+//	dlogits[..., k] = grad[...] * (1{k = actions[...]} - probs[..., k])
+func (c *Categorical) LogProbB(grad, actions tensor.Tensor) (tensor.Tensor,
+	error) {
+	idx, err := c.actionIndices(actions)
+	if err != nil {
+		return nil, fmt.Errorf("logProbB: %v", err)
+	}
+	g, err := c.batchGrad(grad)
+	if err != nil {
+		return nil, fmt.Errorf("logProbB: %v", err)
+	}
+
+	a, _ := intData(idx)
+	probs, _ := toFloat64Data(c.probs)
+	k := c.categories()
+	out := make([]float64, len(probs))
+	for r := range g {
+		if a[r] < 0 || a[r] >= k {
+			return nil, fmt.Errorf("logProbB: index out of range [%v] with "+
+				"%v categories", a[r], k)
+		}
+		for i := 0; i < k; i++ {
+			out[r*k+i] = -g[r] * probs[r*k+i]
+		}
+		out[r*k+a[r]] += g[r]
+	}
+	return fromFloat64Data(c.probs.Dtype(), c.probs.Shape().Clone(), out), nil
+}
+
+// actionIndices checks that actions has the batch shape of the
+// Categorical and returns actions reshaped to have a trailing axis of
+// size 1, so that actions can be used to gather along the last axis
+func (c *Categorical) actionIndices(actions tensor.Tensor) (tensor.Tensor,
+	error) {
+	if !isIntDtype(actions.Dtype()) {
+		return nil, fmt.Errorf("expected actions of an integer type but "+
+			"got %v", actions.Dtype())
+	}
+	if !equalShapes(actions.Shape(), c.BatchShape()) {
+		return nil, fmt.Errorf("expected actions of shape %v but got %v",
+			c.BatchShape(), actions.Shape())
+	}
+
+	shape := append(c.BatchShape(), 1)
+	return reshape(actions, shape)
+}
+
+// batchGrad checks that grad has the batch shape and type of the
+// Categorical and returns its data
+func (c *Categorical) batchGrad(grad tensor.Tensor) ([]float64, error) {
+	if grad.Dtype() != c.probs.Dtype() {
+		return nil, fmt.Errorf("expected grad of type %v but got %v",
+			c.probs.Dtype(), grad.Dtype())
+	}
+	if !equalShapes(grad.Shape(), c.BatchShape()) {
+		return nil, fmt.Errorf("expected grad of shape %v but got %v",
+			c.BatchShape(), grad.Shape())
+	}
+	return toFloat64Data(grad)
+}
+
+// Entropy returns the entropy of each distribution, -sum_k p_k log p_k.
+// The returned tensor has the batch shape of the Categorical.
+// Categories with zero probability do not contribute to the entropy.
+func (c *Categorical) Entropy() tensor.Tensor {
+	probs, _ := toFloat64Data(c.probs)
+	logProbs, _ := toFloat64Data(c.logProbs)
+	return fromFloat64Data(c.probs.Dtype(), c.BatchShape(),
+		c.entropy(probs, logProbs))
+}
+
+// entropy returns the entropy of each distribution
+func (c *Categorical) entropy(probs, logProbs []float64) []float64 {
+	k := c.categories()
+	out := make([]float64, len(probs)/k)
+	for r := range out {
+		for i := r * k; i < (r+1)*k; i++ {
+			if probs[i] > 0 {
+				out[r] -= probs[i] * logProbs[i]
+			}
+		}
+	}
+	return out
+}
+
+// EntropyB is the backward pass of Entropy. Given the gradient grad
+// with respect to the output of Entropy, EntropyB returns the gradient
+// with respect to the logits:
+//
+//	dlogits[..., k] = -grad[...] * probs[..., k] *
+//		(logProbs[..., k] + entropy[...])
+func (c *Categorical) EntropyB(grad tensor.Tensor) (tensor.Tensor, error) {
+	g, err := c.batchGrad(grad)
+	if err != nil {
+		return nil, fmt.Errorf("entropyB: %v", err)
+	}
+
+	probs, _ := toFloat64Data(c.probs)
+	logProbs, _ := toFloat64Data(c.logProbs)
+	entropy := c.entropy(probs, logProbs)
+
+	k := c.categories()
+	out := make([]float64, len(probs))
+	for r := range g {
+		for i := r * k; i < (r+1)*k; i++ {
+			if probs[i] > 0 {
+				out[i] = -g[r] * probs[i] * (logProbs[i] + entropy[r])
+			}
+		}
+	}
+	return fromFloat64Data(c.probs.Dtype(), c.probs.Shape().Clone(), out), nil
+}
+
+// KL returns the Kullback-Leibler divergence KL(c || other) of each
+// distribution in c from the corresponding distribution in other:
+//
+//	KL[...] = sum_k p_k (log p_k - log q_k)
+//
+// where p and q are the probabilities of c and other. Both
+// Categoricals must have logits of the same shape and type. The
+// returned tensor has the batch shape of the Categorical. The
+// divergence is +Inf if q_k is 0 for any category with p_k > 0.
+func (c *Categorical) KL(other *Categorical) (tensor.Tensor, error) {
+	kl, err := c.kl(other)
+	if err != nil {
+		return nil, fmt.Errorf("kl: %v", err)
+	}
+	return fromFloat64Data(c.probs.Dtype(), c.BatchShape(), kl), nil
+}
+
+// kl returns the divergence of each distribution in c from the
+// corresponding distribution in other
+func (c *Categorical) kl(other *Categorical) ([]float64, error) {
+	if c.probs.Dtype() != other.probs.Dtype() {
+		return nil, fmt.Errorf("categoricals must have the same type "+
+			"but got %v and %v", c.probs.Dtype(), other.probs.Dtype())
+	}
+	if !equalShapes(c.probs.Shape(), other.probs.Shape()) {
+		return nil, fmt.Errorf("categoricals must have the same shape "+
+			"but got %v and %v", c.probs.Shape(), other.probs.Shape())
+	}
+
+	p, _ := toFloat64Data(c.probs)
+	logP, _ := toFloat64Data(c.logProbs)
+	logQ, _ := toFloat64Data(other.logProbs)
+
+	k := c.categories()
+	out := make([]float64, len(p)/k)
+	for r := range out {
+		for i := r * k; i < (r+1)*k; i++ {
+			if p[i] > 0 {
+				out[r] += p[i] * (logP[i] - logQ[i])
+			}
+		}
+	}
+	return out, nil
+}
+
+// KLB is the backward pass of KL. Given the gradient grad with respect
+// to the output of KL, KLB returns the gradients with respect to the
+// logits of c and of other:
+//
+//	dlogits[..., k] = grad[...] * p_k * (log p_k - log q_k - KL[...])
+//	dother[..., k] = grad[...] * (q_k - p_k)
+func (c *Categorical) KLB(grad tensor.Tensor,
+	other *Categorical) (tensor.Tensor, tensor.Tensor, error) {
+	kl, err := c.kl(other)
+	if err != nil {
+		return nil, nil, fmt.Errorf("klB: %v", err)
+	}
+	g, err := c.batchGrad(grad)
+	if err != nil {
+		return nil, nil, fmt.Errorf("klB: %v", err)
+	}
+
+	p, _ := toFloat64Data(c.probs)
+	q, _ := toFloat64Data(other.probs)
+	logP, _ := toFloat64Data(c.logProbs)
+	logQ, _ := toFloat64Data(other.logProbs)
+
+	k := c.categories()
+	dp := make([]float64, len(p))
+	dq := make([]float64, len(p))
+	for r := range g {
+		for i := r * k; i < (r+1)*k; i++ {
+			if p[i] > 0 {
+				dp[i] = g[r] * p[i] * (logP[i] - logQ[i] - kl[r])
+			}
+			dq[i] = g[r] * (q[i] - p[i])
+		}
+	}
+
+	dt := c.probs.Dtype()
+	shape := c.probs.Shape()
+	return fromFloat64Data(dt, shape.Clone(), dp),
+		fromFloat64Data(dt, shape.Clone(), dq), nil
+}
@@ -0,0 +1,232 @@
+package top
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestCategorical(t *testing.T) {
+	probs := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{0.2, 0.3, 0.5, 0, 0.25, 0.75}),
+	)
+	c, err := NewCategoricalFromProbs(probs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(c.Probs(), probs, 1e-12) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", probs, c.Probs())
+	}
+
+	actions := tensor.New(tensor.WithShape(2),
+		tensor.WithBacking([]int32{2, 1}))
+	logProb, err := c.LogProb(actions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{math.Log(0.5), math.Log(0.25)}),
+	)
+	if !closeTo(logProb, target, 1e-12) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, logProb)
+	}
+
+	// Categories with zero probability do not contribute to the entropy
+	target = tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{
+			-0.2*math.Log(0.2) - 0.3*math.Log(0.3) - 0.5*math.Log(0.5),
+			-0.25*math.Log(0.25) - 0.75*math.Log(0.75),
+		}),
+	)
+	if entropy := c.Entropy(); !closeTo(entropy, target, 1e-12) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, entropy)
+	}
+
+	uniform, err := NewCategorical(tensor.New(tensor.WithShape(2, 3),
+		tensor.WithBacking([]float64{1, 1, 1, 5, 5, 5})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kl, err := c.KL(uniform)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target = tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{
+			math.Log(3) - c.Entropy().Data().([]float64)[0],
+			math.Log(3) - c.Entropy().Data().([]float64)[1],
+		}),
+	)
+	if !closeTo(kl, target, 1e-12) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, kl)
+	}
+
+	// The divergence from a distribution with zero probability where c
+	// has non-zero probability is infinite
+	kl, err = uniform.KL(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := kl.Data().([]float64)[1]; !math.IsInf(v, 1) {
+		t.Errorf("expected +Inf divergence but got %v", v)
+	}
+
+	// Errors
+	if _, err := c.LogProb(tensor.New(tensor.WithShape(2),
+		tensor.WithBacking([]float64{0, 1}))); err == nil {
+		t.Error("expected error for float actions")
+	}
+	if _, err := c.LogProb(tensor.New(tensor.WithShape(2, 1),
+		tensor.WithBacking([]int{0, 1}))); err == nil {
+		t.Error("expected error for actions of wrong shape")
+	}
+	if _, err := c.LogProb(tensor.New(tensor.WithShape(2),
+		tensor.WithBacking([]int{0, 3}))); err == nil {
+		t.Error("expected error for actions out of range")
+	}
+	if _, err := c.KL(&Categorical{
+		logProbs: tensor.New(tensor.Of(tensor.Float64), tensor.WithShape(3)),
+		probs:    tensor.New(tensor.Of(tensor.Float64), tensor.WithShape(3)),
+	}); err == nil {
+		t.Error("expected error for categoricals of different shapes")
+	}
+	if _, err := NewCategoricalFromProbs(tensor.New(tensor.WithShape(2),
+		tensor.WithBacking([]float64{0.5, -0.5}))); err == nil {
+		t.Error("expected error for negative probabilities")
+	}
+	if _, err := NewCategoricalFromProbs(tensor.New(tensor.WithShape(2),
+		tensor.WithBacking([]float64{0, 0}))); err == nil {
+		t.Error("expected error for probabilities summing to 0")
+	}
+	if _, err := NewCategorical(tensor.New(
+		tensor.FromScalar(1.0))); err == nil {
+		t.Error("expected error for scalar logits")
+	}
+}
+
+func TestCategoricalSample(t *testing.T) {
+	const samples = 20000
+
+	logits := tensor.NewDense(
+		tensor.Float32,
+		[]int{3},
+		tensor.WithBacking([]float32{
+			float32(math.Log(0.1)), float32(math.Inf(-1)),
+			float32(math.Log(0.9)),
+		}),
+	)
+	c, err := NewCategorical(logits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.BatchShape()) != 0 {
+		t.Errorf("expected scalar batch shape but got %v", c.BatchShape())
+	}
+
+	src := rand.NewSource(1)
+	counts := make([]int, 3)
+	for i := 0; i < samples; i++ {
+		sample, err := c.Sample(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sample.Dtype() != tensor.Int || len(sample.Shape()) != 0 {
+			t.Fatalf("expected scalar int sample but got %v of shape %v",
+				sample.Dtype(), sample.Shape())
+		}
+		counts[sample.ScalarValue().(int)]++
+	}
+
+	if counts[1] != 0 {
+		t.Errorf("sampled category with zero probability %v times",
+			counts[1])
+	}
+	if freq := float64(counts[0]) / samples; math.Abs(freq-0.1) > 0.01 {
+		t.Errorf("expected category 0 with frequency 0.1 but got %v", freq)
+	}
+}
+
+func TestCategoricalGrad(t *testing.T) {
+	logits := randTensor([]int{2, 4}, -2, 2)
+	other := randTensor([]int{2, 4}, -2, 2)
+	actions := tensor.New(tensor.WithShape(2),
+		tensor.WithBacking([]int{3, 0}))
+
+	checkGrad(t, "logProb",
+		func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+			c, err := NewCategorical(inputs[0])
+			if err != nil {
+				return nil, err
+			}
+			return c.LogProb(actions)
+		},
+		func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			c, err := NewCategorical(inputs[0])
+			if err != nil {
+				return nil, err
+			}
+			dx, err := c.LogProbB(grad, actions)
+			return []tensor.Tensor{dx}, err
+		},
+		logits,
+	)
+
+	checkGrad(t, "entropy",
+		func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+			c, err := NewCategorical(inputs[0])
+			if err != nil {
+				return nil, err
+			}
+			return c.Entropy(), nil
+		},
+		func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			c, err := NewCategorical(inputs[0])
+			if err != nil {
+				return nil, err
+			}
+			dx, err := c.EntropyB(grad)
+			return []tensor.Tensor{dx}, err
+		},
+		logits,
+	)
+
+	checkGrad(t, "kl",
+		func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+			p, err := NewCategorical(inputs[0])
+			if err != nil {
+				return nil, err
+			}
+			q, err := NewCategorical(inputs[1])
+			if err != nil {
+				return nil, err
+			}
+			return p.KL(q)
+		},
+		func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			p, err := NewCategorical(inputs[0])
+			if err != nil {
+				return nil, err
+			}
+			q, err := NewCategorical(inputs[1])
+			if err != nil {
+				return nil, err
+			}
+			dp, dq, err := p.KLB(grad, q)
+			return []tensor.Tensor{dp, dq}, err
+		},
+		logits, other,
+	)
+}
@@ -0,0 +1,108 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// Clamp clamps the elements of in to the range [min, max], so that
+// elements smaller than min are set to min and elements larger than
+// max are set to max. This function works for tensors storing float64,
+// float32, or any integer data type. If an integer data type is used,
+// the result will be a tensor of type tensor.Int regardless of the
+// input integer type.
+//
+// For float64 and float32 tensors, min and max must have the same type
+// as the data stored in in. For integer tensors, min and max may be of
+// any integer type.
+func Clamp(in tensor.Tensor, min, max interface{}) (tensor.Tensor, error) {
+	switch in.Dtype() {
+	case tensor.Float64:
+		fMin, okMin := min.(float64)
+		fMax, okMax := max.(float64)
+		if !okMin || !okMax {
+			return nil, fmt.Errorf("clamp: data type of min (%T) and max "+
+				"(%T) must match data type of in (%v)", min, max, in.Dtype())
+		}
+		if fMin > fMax {
+			return nil, fmt.Errorf("clamp: min (%v) must not be larger "+
+				"than max (%v)", fMin, fMax)
+		}
+
+		data, _ := float64Data(in)
+		out := make([]float64, len(data))
+		for i, v := range data {
+			switch {
+			case v < fMin:
+				out[i] = fMin
+			case v > fMax:
+				out[i] = fMax
+			default:
+				out[i] = v
+			}
+		}
+		return newTensor(in.Shape().Clone(), out), nil
+
+	case tensor.Float32:
+		fMin, okMin := min.(float32)
+		fMax, okMax := max.(float32)
+		if !okMin || !okMax {
+			return nil, fmt.Errorf("clamp: data type of min (%T) and max "+
+				"(%T) must match data type of in (%v)", min, max, in.Dtype())
+		}
+		if fMin > fMax {
+			return nil, fmt.Errorf("clamp: min (%v) must not be larger "+
+				"than max (%v)", fMin, fMax)
+		}
+
+		data, _ := float32Data(in)
+		out := make([]float32, len(data))
+		for i, v := range data {
+			switch {
+			case v < fMin:
+				out[i] = fMin
+			case v > fMax:
+				out[i] = fMax
+			default:
+				out[i] = v
+			}
+		}
+		return newTensor(in.Shape().Clone(), out), nil
+
+	case tensor.Int, tensor.Int8, tensor.Int16, tensor.Int32, tensor.Int64,
+		tensor.Uint, tensor.Uint8, tensor.Uint16, tensor.Uint32, tensor.Uint64:
+		iMin, err := anyIntToInt(min)
+		if err != nil {
+			return nil, fmt.Errorf("clamp: could not convert min type %T "+
+				"to int", min)
+		}
+		iMax, err := anyIntToInt(max)
+		if err != nil {
+			return nil, fmt.Errorf("clamp: could not convert max type %T "+
+				"to int", max)
+		}
+		if iMin > iMax {
+			return nil, fmt.Errorf("clamp: min (%v) must not be larger "+
+				"than max (%v)", iMin, iMax)
+		}
+
+		data, _ := intData(in)
+		out := make([]int, len(data))
+		for i, v := range data {
+			switch {
+			case v < iMin:
+				out[i] = iMin
+			case v > iMax:
+				out[i] = iMax
+			default:
+				out[i] = v
+			}
+		}
+		return newTensor(in.Shape().Clone(), out), nil
+
+	default:
+		return nil, fmt.Errorf("clamp: cannot clamp tensor of type %v",
+			in.Dtype())
+	}
+}
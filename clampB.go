@@ -6,7 +6,10 @@ import (
 	"gorgonia.org/tensor"
 )
 
-// ClampB is the backward pass of Clamp. This function works for tensors
+// ClampB is the backward pass of Clamp, returning a mask which is 1
+// where in lies within [min, max] and 0 elsewhere. Multiply the mask
+// elementwise with the gradient of the output of Clamp to compute the
+// gradient of the input of Clamp. This function works for tensors
 // storing float64, float32, or any integer data type. If an integer
 // data type is used, the result will be a tensor of type tensor.Int
 // regardless of the input integer type.
@@ -141,8 +141,8 @@ func TestIntClampB(t *testing.T) {
 		numDims := rand.Intn(dimMax-dimMin) + dimMin
 		size := randInt(numDims, sizeMin, sizeMax)
 
-		clampMin := -rand.Intn(clipScale) // [-clipScale, 0)
-		clampMax := rand.Intn(clipScale)  // [0, clipScale)
+		clampMin := -rand.Intn(clipScale)    // [-clipScale, 0)
+		clampMax := rand.Intn(clipScale) + 1 // (0, clipScale]
 
 		min := clampMin * scale
 		max := clampMax * scale
@@ -0,0 +1,46 @@
+package top
+
+import (
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestClamp(t *testing.T) {
+	ins := []tensor.Tensor{
+		tensor.NewDense(tensor.Float64, []int{2, 3},
+			tensor.WithBacking([]float64{-3, -1, 0, 0.5, 1, 4})),
+		tensor.NewDense(tensor.Float32, []int{2, 3},
+			tensor.WithBacking([]float32{-3, -1, 0, 0.5, 1, 4})),
+		tensor.NewDense(tensor.Uint8, []int{2, 3},
+			tensor.WithBacking([]uint8{0, 1, 2, 3, 4, 5})),
+	}
+	mins := []interface{}{-1.0, float32(-1), int32(1)}
+	maxs := []interface{}{0.5, float32(0.5), 3}
+	outs := []tensor.Tensor{
+		tensor.NewDense(tensor.Float64, []int{2, 3},
+			tensor.WithBacking([]float64{-1, -1, 0, 0.5, 0.5, 0.5})),
+		tensor.NewDense(tensor.Float32, []int{2, 3},
+			tensor.WithBacking([]float32{-1, -1, 0, 0.5, 0.5, 0.5})),
+		tensor.NewDense(tensor.Int, []int{2, 3},
+			tensor.WithBacking([]int{1, 1, 2, 3, 3, 3})),
+	}
+
+	for i := range ins {
+		pred, err := Clamp(ins[i], mins[i], maxs[i])
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !pred.Eq(outs[i]) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", outs[i], pred)
+		}
+	}
+
+	if _, err := Clamp(ins[0], float32(-1), 1.0); err == nil {
+		t.Error("expected error for mismatched min type")
+	}
+	if _, err := Clamp(ins[0], 1.0, -1.0); err == nil {
+		t.Error("expected error for min larger than max")
+	}
+}
@@ -9,6 +9,12 @@ import (
 // GatherB is the backpropagation of Gather. The input tensor t must
 // store either float64's, float32's, or any integer type.
 //
+// GatherB returns a mask which is 1 at each element of t gathered by
+// indices and 0 elsewhere. This mask is the gradient of the sum of the
+// output of Gather only if no element is gathered more than once. To
+// compute the gradient of Gather given the gradient with respect to its
+// output, use ScatterAdd instead.
+//
 // Regardless of the integer type stored in the input tensors, this
 // function will convert that integer type to an int before computing
 // the gather backpropagation. If using a 32-bit machine, use caution
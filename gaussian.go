@@ -0,0 +1,528 @@
+package top
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gorgonia.org/tensor"
+)
+
+// logSqrt2Pi is log(sqrt(2 * pi))
+var logSqrt2Pi = 0.5 * math.Log(2*math.Pi)
+
+// DiagGaussian is a batch of multivariate Gaussian distributions with
+// diagonal covariance, parameterized by their means and the logarithms
+// of their standard deviations. The dimensions of each distribution lie
+// along the last axis of the mean and log standard deviation, which is
+// called the action axis, and all leading axes are batch axes. For
+// example, a mean of shape (B, D) defines B distributions over R^D, and
+// the batch shape is (B).
+//
+// DiagGaussian works with float64 and float32 parameters. Tensors
+// returned by its methods have the same type as the parameters.
+//
+// Each method computing a quantity from the parameters has a
+// corresponding backward method, suffixed with B, which returns the
+// gradients with respect to the mean and log standard deviation.
+type DiagGaussian struct {
+	mean   tensor.Tensor
+	logStd tensor.Tensor
+}
+
+// NewDiagGaussian returns a new DiagGaussian with the argument mean and
+// log standard deviation, which must have the same shape and type
+func NewDiagGaussian(mean, logStd tensor.Tensor) (*DiagGaussian, error) {
+	if mean.Dtype() != tensor.Float64 && mean.Dtype() != tensor.Float32 {
+		return nil, fmt.Errorf("newDiagGaussian: expected mean of type %v "+
+			"or %v but got %v", tensor.Float64, tensor.Float32, mean.Dtype())
+	}
+	if logStd.Dtype() != mean.Dtype() {
+		return nil, fmt.Errorf("newDiagGaussian: mean and logStd must "+
+			"have the same type but got %v and %v", mean.Dtype(),
+			logStd.Dtype())
+	}
+	if !equalShapes(mean.Shape(), logStd.Shape()) {
+		return nil, fmt.Errorf("newDiagGaussian: mean and logStd must "+
+			"have the same shape but got %v and %v", mean.Shape(),
+			logStd.Shape())
+	}
+	if len(mean.Shape()) == 0 {
+		return nil, fmt.Errorf("newDiagGaussian: expected mean with at " +
+			"least 1 dimension but got a scalar")
+	}
+	return &DiagGaussian{mean: mean, logStd: logStd}, nil
+}
+
+// Mean returns the mean of the DiagGaussian. The returned tensor should
+// not be modified.
+func (d *DiagGaussian) Mean() tensor.Tensor {
+	return d.mean
+}
+
+// LogStd returns the log standard deviation of the DiagGaussian. The
+// returned tensor should not be modified.
+func (d *DiagGaussian) LogStd() tensor.Tensor {
+	return d.logStd
+}
+
+// BatchShape returns the shape of the batch of distributions, which is
+// the shape of the mean without the action axis
+func (d *DiagGaussian) BatchShape() tensor.Shape {
+	shape := d.mean.Shape()
+	return shape[:len(shape)-1].Clone()
+}
+
+// dims returns the number of dimensions of each distribution
+func (d *DiagGaussian) dims() int {
+	shape := d.mean.Shape()
+	return shape[len(shape)-1]
+}
+
+// params returns the data of the mean and log standard deviation
+func (d *DiagGaussian) params() ([]float64, []float64) {
+	mean, _ := toFloat64Data(d.mean)
+	logStd, _ := toFloat64Data(d.logStd)
+	return mean, logStd
+}
+
+// fromData returns a new tensor with the type of the parameters and the
+// argument shape holding data
+func (d *DiagGaussian) fromData(shape tensor.Shape,
+	data []float64) tensor.Tensor {
+	return fromFloat64Data(d.mean.Dtype(), shape, data)
+}
+
+// Sample draws a sample from each distribution using src as the source
+// of randomness. The returned tensor has the shape of the mean.
+func (d *DiagGaussian) Sample(src rand.Source) tensor.Tensor {
+	sample, _ := d.RSample(src)
+	return sample
+}
+
+// RSample draws a reparameterized sample from each distribution using
+// src as the source of randomness, returning the sample together with
+// the standard normal noise from which it was computed:
+//
+//	sample = mean + exp(logStd) * noise
+//
+// The gradient of the sample with respect to the parameters is computed
+// from the noise by RSampleB.
+func (d *DiagGaussian) RSample(src rand.Source) (tensor.Tensor,
+	tensor.Tensor) {
+	rng := rand.New(src)
+	mean, logStd := d.params()
+
+	sample := make([]float64, len(mean))
+	noise := make([]float64, len(mean))
+	for i := range noise {
+		noise[i] = rng.NormFloat64()
+		sample[i] = mean[i] + math.Exp(logStd[i])*noise[i]
+	}
+	shape := d.mean.Shape()
+	return d.fromData(shape.Clone(), sample), d.fromData(shape.Clone(), noise)
+}
+
+// RSampleB is the backward pass of RSample. Given the gradient grad
+// with respect to a sample returned by RSample and the noise returned
+// with it, RSampleB returns the gradients with respect to the mean and
+// log standard deviation:
+//
+//	dmean = grad
+//	dlogStd = grad * exp(logStd) * noise
+func (d *DiagGaussian) RSampleB(grad, noise tensor.Tensor) (tensor.Tensor,
+	tensor.Tensor, error) {
+	g, err := d.checkParamShape(grad)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rSampleB: %v", err)
+	}
+	n, err := d.checkParamShape(noise)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rSampleB: noise: %v", err)
+	}
+
+	_, logStd := d.params()
+	dMean := make([]float64, len(g))
+	dLogStd := make([]float64, len(g))
+	for i := range g {
+		dMean[i] = g[i]
+		dLogStd[i] = g[i] * math.Exp(logStd[i]) * n[i]
+	}
+	shape := d.mean.Shape()
+	return d.fromData(shape.Clone(), dMean),
+		d.fromData(shape.Clone(), dLogStd), nil
+}
+
+// LogProb returns the log-density of x under each distribution, summed
+// over the action axis. The x tensor must have the shape and type of
+// the mean, and the returned tensor has the batch shape of the
+// DiagGaussian.
+func (d *DiagGaussian) LogProb(x tensor.Tensor) (tensor.Tensor, error) {
+	data, err := d.checkParamShape(x)
+	if err != nil {
+		return nil, fmt.Errorf("logProb: %v", err)
+	}
+
+	mean, logStd := d.params()
+	k := d.dims()
+	out := make([]float64, len(mean)/k)
+	for r := range out {
+		for i := r * k; i < (r+1)*k; i++ {
+			z := (data[i] - mean[i]) / math.Exp(logStd[i])
+			out[r] -= 0.5*z*z + logStd[i] + logSqrt2Pi
+		}
+	}
+	return d.fromData(d.BatchShape(), out), nil
+}
+
+// LogProbB is the backward pass of LogProb. Given the gradient grad
+// with respect to the output of LogProb and the x passed to LogProb,
+// LogProbB returns the gradients with respect to the mean and log
+// standard deviation:
+//
+//	dmean = grad * (x - mean) / exp(2 * logStd)
+//	dlogStd = grad * ((x - mean)^2 / exp(2 * logStd) - 1)
+//
+// The gradient with respect to x is the negation of dmean.
+func (d *DiagGaussian) LogProbB(grad, x tensor.Tensor) (tensor.Tensor,
+	tensor.Tensor, error) {
+	g, err := d.batchGrad(grad)
+	if err != nil {
+		return nil, nil, fmt.Errorf("logProbB: %v", err)
+	}
+	data, err := d.checkParamShape(x)
+	if err != nil {
+		return nil, nil, fmt.Errorf("logProbB: %v", err)
+	}
+
+	mean, logStd := d.params()
+	k := d.dims()
+	dMean := make([]float64, len(mean))
+	dLogStd := make([]float64, len(mean))
+	for r := range g {
+		for i := r * k; i < (r+1)*k; i++ {
+			std := math.Exp(logStd[i])
+			z := (data[i] - mean[i]) / std
+			dMean[i] = g[r] * z / std
+			dLogStd[i] = g[r] * (z*z - 1)
+		}
+	}
+	shape := d.mean.Shape()
+	return d.fromData(shape.Clone(), dMean),
+		d.fromData(shape.Clone(), dLogStd), nil
+}
+
+// Entropy returns the differential entropy of each distribution:
+//
+//	entropy = sum(logStd + 0.5 * log(2 * pi * e))
+//
+// where the sum is over the action axis. The returned tensor has the
+// batch shape of the DiagGaussian.
+func (d *DiagGaussian) Entropy() tensor.Tensor {
+	_, logStd := d.params()
+	k := d.dims()
+	out := make([]float64, len(logStd)/k)
+	for r := range out {
+		for i := r * k; i < (r+1)*k; i++ {
+			out[r] += logStd[i] + logSqrt2Pi + 0.5
+		}
+	}
+	return d.fromData(d.BatchShape(), out)
+}
+
+// EntropyB is the backward pass of Entropy. Given the gradient grad
+// with respect to the output of Entropy, EntropyB returns the gradients
+// with respect to the mean, which is zero, and the log standard
+// deviation, which is grad broadcast along the action axis.
+func (d *DiagGaussian) EntropyB(grad tensor.Tensor) (tensor.Tensor,
+	tensor.Tensor, error) {
+	g, err := d.batchGrad(grad)
+	if err != nil {
+		return nil, nil, fmt.Errorf("entropyB: %v", err)
+	}
+
+	k := d.dims()
+	dLogStd := make([]float64, len(g)*k)
+	for i := range dLogStd {
+		dLogStd[i] = g[i/k]
+	}
+	shape := d.mean.Shape()
+	return zerosLike(d.mean), d.fromData(shape.Clone(), dLogStd), nil
+}
+
+// KL returns the Kullback-Leibler divergence KL(d || other) of each
+// distribution in d from the corresponding distribution in other,
+// summed over the action axis:
+//
+//	KL = sum(logStdQ - logStdP +
+//		(stdP^2 + (meanP - meanQ)^2) / (2 * stdQ^2) - 0.5)
+//
+// Both DiagGaussians must have parameters of the same shape and type.
+// The returned tensor has the batch shape of the DiagGaussian.
+func (d *DiagGaussian) KL(other *DiagGaussian) (tensor.Tensor, error) {
+	if err := d.checkOther(other); err != nil {
+		return nil, fmt.Errorf("kl: %v", err)
+	}
+
+	meanP, logStdP := d.params()
+	meanQ, logStdQ := other.params()
+	k := d.dims()
+	out := make([]float64, len(meanP)/k)
+	for r := range out {
+		for i := r * k; i < (r+1)*k; i++ {
+			varQ := math.Exp(2 * logStdQ[i])
+			diff := meanP[i] - meanQ[i]
+			out[r] += logStdQ[i] - logStdP[i] - 0.5 +
+				(math.Exp(2*logStdP[i])+diff*diff)/(2*varQ)
+		}
+	}
+	return d.fromData(d.BatchShape(), out), nil
+}
+
+// KLB is the backward pass of KL. Given the gradient grad with respect
+// to the output of KL, KLB returns the gradients with respect to the
+// mean and log standard deviation of d, followed by the gradients with
+// respect to the mean and log standard deviation of other.
+func (d *DiagGaussian) KLB(grad tensor.Tensor,
+	other *DiagGaussian) (dMeanP, dLogStdP, dMeanQ, dLogStdQ tensor.Tensor,
+	err error) {
+	if err := d.checkOther(other); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("klB: %v", err)
+	}
+	g, err := d.batchGrad(grad)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("klB: %v", err)
+	}
+
+	meanP, logStdP := d.params()
+	meanQ, logStdQ := other.params()
+	k := d.dims()
+	dmp := make([]float64, len(meanP))
+	dlp := make([]float64, len(meanP))
+	dmq := make([]float64, len(meanP))
+	dlq := make([]float64, len(meanP))
+	for r := range g {
+		for i := r * k; i < (r+1)*k; i++ {
+			varP := math.Exp(2 * logStdP[i])
+			varQ := math.Exp(2 * logStdQ[i])
+			diff := meanP[i] - meanQ[i]
+
+			dmp[i] = g[r] * diff / varQ
+			dmq[i] = -dmp[i]
+			dlp[i] = g[r] * (varP/varQ - 1)
+			dlq[i] = g[r] * (1 - (varP+diff*diff)/varQ)
+		}
+	}
+
+	shape := d.mean.Shape()
+	return d.fromData(shape.Clone(), dmp), d.fromData(shape.Clone(), dlp),
+		d.fromData(shape.Clone(), dmq), d.fromData(shape.Clone(), dlq), nil
+}
+
+// checkOther checks that other has parameters of the same shape and
+// type as d
+func (d *DiagGaussian) checkOther(other *DiagGaussian) error {
+	if other.mean.Dtype() != d.mean.Dtype() {
+		return fmt.Errorf("gaussians must have the same type but got %v "+
+			"and %v", d.mean.Dtype(), other.mean.Dtype())
+	}
+	if !equalShapes(other.mean.Shape(), d.mean.Shape()) {
+		return fmt.Errorf("gaussians must have the same shape but got %v "+
+			"and %v", d.mean.Shape(), other.mean.Shape())
+	}
+	return nil
+}
+
+// checkParamShape checks that t has the shape and type of the
+// parameters of the DiagGaussian and returns its data
+func (d *DiagGaussian) checkParamShape(t tensor.Tensor) ([]float64,
+	error) {
+	if t.Dtype() != d.mean.Dtype() {
+		return nil, fmt.Errorf("expected tensor of type %v but got %v",
+			d.mean.Dtype(), t.Dtype())
+	}
+	if !equalShapes(t.Shape(), d.mean.Shape()) {
+		return nil, fmt.Errorf("expected tensor of shape %v but got %v",
+			d.mean.Shape(), t.Shape())
+	}
+	return toFloat64Data(t)
+}
+
+// batchGrad checks that grad has the batch shape of the DiagGaussian
+// and the type of its parameters and returns its data
+func (d *DiagGaussian) batchGrad(grad tensor.Tensor) ([]float64, error) {
+	if grad.Dtype() != d.mean.Dtype() {
+		return nil, fmt.Errorf("expected grad of type %v but got %v",
+			d.mean.Dtype(), grad.Dtype())
+	}
+	if !equalShapes(grad.Shape(), d.BatchShape()) {
+		return nil, fmt.Errorf("expected grad of shape %v but got %v",
+			d.BatchShape(), grad.Shape())
+	}
+	return toFloat64Data(grad)
+}
+
+// TanhDiagGaussian is a DiagGaussian whose samples are squashed into
+// (-1, 1) by tanh, as used for bounded actions in Soft Actor-Critic.
+// A sample is computed as action = tanh(pre), where pre is a sample of
+// the underlying DiagGaussian.
+//
+// Since tanh saturates in floating point, the log-density of an action
+// is computed from its pre-squashed value, which is returned alongside
+// each sample. The log-density includes the correction for the log
+// determinant of the Jacobian of tanh:
+//
+//	log p(action) = log N(pre) - sum(log(1 - tanh(pre)^2))
+//
+// TanhDiagGaussian has no closed-form entropy.
+type TanhDiagGaussian struct {
+	base *DiagGaussian
+}
+
+// NewTanhDiagGaussian returns a new TanhDiagGaussian whose underlying
+// DiagGaussian has the argument mean and log standard deviation. See
+// NewDiagGaussian for more details.
+func NewTanhDiagGaussian(mean, logStd tensor.Tensor) (*TanhDiagGaussian,
+	error) {
+	base, err := NewDiagGaussian(mean, logStd)
+	if err != nil {
+		return nil, fmt.Errorf("newTanhDiagGaussian: %v", err)
+	}
+	return &TanhDiagGaussian{base: base}, nil
+}
+
+// Base returns the underlying DiagGaussian
+func (t *TanhDiagGaussian) Base() *DiagGaussian {
+	return t.base
+}
+
+// Sample draws a squashed sample from each distribution using src as
+// the source of randomness, returning the sample together with its
+// pre-squashed value
+func (t *TanhDiagGaussian) Sample(src rand.Source) (tensor.Tensor,
+	tensor.Tensor) {
+	action, pre, _ := t.RSample(src)
+	return action, pre
+}
+
+// RSample draws a reparameterized squashed sample from each
+// distribution using src as the source of randomness, returning the
+// sample, its pre-squashed value and the standard normal noise from
+// which it was computed:
+//
+//	action = tanh(pre) = tanh(mean + exp(logStd) * noise)
+func (t *TanhDiagGaussian) RSample(src rand.Source) (action, pre,
+	noise tensor.Tensor) {
+	pre, noise = t.base.RSample(src)
+	data, _ := toFloat64Data(pre)
+	out := make([]float64, len(data))
+	for i := range data {
+		out[i] = math.Tanh(data[i])
+	}
+	return t.base.fromData(pre.Shape().Clone(), out), pre, noise
+}
+
+// RSampleB is the backward pass of RSample. Given the gradient grad
+// with respect to a squashed sample returned by RSample and the noise
+// returned with it, RSampleB returns the gradients with respect to the
+// mean and log standard deviation.
+func (t *TanhDiagGaussian) RSampleB(grad, noise tensor.Tensor) (tensor.Tensor,
+	tensor.Tensor, error) {
+	g, err := t.base.checkParamShape(grad)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rSampleB: %v", err)
+	}
+	n, err := t.base.checkParamShape(noise)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rSampleB: noise: %v", err)
+	}
+
+	// Backpropagate through tanh to the pre-squashed sample
+	mean, logStd := t.base.params()
+	dPre := make([]float64, len(g))
+	for i := range g {
+		action := math.Tanh(mean[i] + math.Exp(logStd[i])*n[i])
+		dPre[i] = g[i] * (1 - action*action)
+	}
+
+	dMean, dLogStd, err := t.base.RSampleB(
+		t.base.fromData(grad.Shape().Clone(), dPre), noise)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rSampleB: %v", err)
+	}
+	return dMean, dLogStd, nil
+}
+
+// LogProb returns the log-density of the squashed sample tanh(pre)
+// under each distribution, summed over the action axis. The pre tensor
+// holds the pre-squashed samples and must have the shape and type of
+// the mean. The returned tensor has the batch shape of the
+// TanhDiagGaussian.
+func (t *TanhDiagGaussian) LogProb(pre tensor.Tensor) (tensor.Tensor, error) {
+	logProb, err := t.base.LogProb(pre)
+	if err != nil {
+		return nil, fmt.Errorf("logProb: %v", err)
+	}
+
+	data, _ := toFloat64Data(pre)
+	out, _ := toFloat64Data(logProb)
+	out = append([]float64(nil), out...)
+	k := t.base.dims()
+	for r := range out {
+		for _, u := range data[r*k : (r+1)*k] {
+			out[r] -= logTanhDerivative(u)
+		}
+	}
+	return t.base.fromData(t.base.BatchShape(), out), nil
+}
+
+// LogProbB is the backward pass of LogProb. Given the gradient grad
+// with respect to the output of LogProb and the pre-squashed samples
+// passed to LogProb, LogProbB returns the gradients with respect to the
+// mean, the log standard deviation and the pre-squashed samples. The
+// gradient with respect to the pre-squashed samples is needed when
+// they were computed by RSample, in which case it should be
+// backpropagated to the parameters with DiagGaussian.RSampleB.
+func (t *TanhDiagGaussian) LogProbB(grad, pre tensor.Tensor) (dMean, dLogStd,
+	dPre tensor.Tensor, err error) {
+	dMean, dLogStd, err = t.base.LogProbB(grad, pre)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("logProbB: %v", err)
+	}
+
+	// d/du [log N(u) - log(1 - tanh(u)^2)] = -dmean + 2 * tanh(u)
+	g, _ := toFloat64Data(grad)
+	data, _ := toFloat64Data(pre)
+	dm, _ := toFloat64Data(dMean)
+	k := t.base.dims()
+	out := make([]float64, len(data))
+	for i, u := range data {
+		out[i] = -dm[i] + 2*g[i/k]*math.Tanh(u)
+	}
+	return dMean, dLogStd, t.base.fromData(pre.Shape().Clone(), out), nil
+}
+
+// KL returns the Kullback-Leibler divergence KL(t || other) of each
+// distribution in t from the corresponding distribution in other.
+// Since tanh is a bijection, this is the divergence between the
+// underlying DiagGaussians. See DiagGaussian.KL for more details.
+func (t *TanhDiagGaussian) KL(other *TanhDiagGaussian) (tensor.Tensor,
+	error) {
+	return t.base.KL(other.base)
+}
+
+// KLB is the backward pass of KL. See DiagGaussian.KLB for more
+// details.
+func (t *TanhDiagGaussian) KLB(grad tensor.Tensor,
+	other *TanhDiagGaussian) (dMeanP, dLogStdP, dMeanQ, dLogStdQ tensor.Tensor,
+	err error) {
+	return t.base.KLB(grad, other.base)
+}
+
+// logTanhDerivative returns log(1 - tanh(u)^2) computed stably as
+// 2 * (log(2) - u - softplus(-2u))
+func logTanhDerivative(u float64) float64 {
+	x := -2 * u
+	softplus := math.Max(x, 0) + math.Log1p(math.Exp(-math.Abs(x)))
+	return 2 * (math.Ln2 - u - softplus)
+}
@@ -0,0 +1,336 @@
+package top
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestDiagGaussian(t *testing.T) {
+	mean := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 2},
+		tensor.WithBacking([]float64{0, 1, -1, 2}),
+	)
+	logStd := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 2},
+		tensor.WithBacking([]float64{0, math.Log(2), 0, math.Log(0.5)}),
+	)
+	d, err := NewDiagGaussian(mean, logStd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 2},
+		tensor.WithBacking([]float64{1, 1, -1, 3}),
+	)
+	logProb, err := d.LogProb(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// normal returns the log-density of x under N(mean, std^2)
+	normal := func(x, mean, std float64) float64 {
+		z := (x - mean) / std
+		return -0.5*z*z - math.Log(std) - 0.5*math.Log(2*math.Pi)
+	}
+	target := tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{
+			normal(1, 0, 1) + normal(1, 1, 2),
+			normal(-1, -1, 1) + normal(3, 2, 0.5),
+		}),
+	)
+	if !closeTo(logProb, target, 1e-12) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, logProb)
+	}
+
+	h := 0.5 * math.Log(2*math.Pi*math.E)
+	target = tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{2*h + math.Log(2), 2*h + math.Log(0.5)}),
+	)
+	if entropy := d.Entropy(); !closeTo(entropy, target, 1e-12) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, entropy)
+	}
+
+	kl, err := d.KL(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(kl, zerosLike(kl), 1e-12) {
+		t.Errorf("expected zero divergence but got \n%v", kl)
+	}
+
+	// Errors
+	if _, err := NewDiagGaussian(mean, tensor.New(tensor.WithShape(4),
+		tensor.WithBacking([]float64{0, 0, 0, 0}))); err == nil {
+		t.Error("expected error for parameters of different shapes")
+	}
+	if _, err := NewDiagGaussian(mean, tensor.New(tensor.WithShape(2, 2),
+		tensor.WithBacking([]float32{0, 0, 0, 0}))); err == nil {
+		t.Error("expected error for parameters of different types")
+	}
+	if _, err := d.LogProb(tensor.New(tensor.WithShape(2),
+		tensor.WithBacking([]float64{0, 0}))); err == nil {
+		t.Error("expected error for x of wrong shape")
+	}
+}
+
+func TestDiagGaussianSample(t *testing.T) {
+	const samples = 20000
+
+	mean := tensor.New(tensor.WithShape(2),
+		tensor.WithBacking([]float32{1, -2}))
+	logStd := tensor.New(tensor.WithShape(2),
+		tensor.WithBacking([]float32{0, float32(math.Log(3))}))
+	d, err := NewDiagGaussian(mean, logStd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := rand.NewSource(1)
+	sum := make([]float64, 2)
+	sumSq := make([]float64, 2)
+	for i := 0; i < samples; i++ {
+		sample := d.Sample(src)
+		if sample.Dtype() != tensor.Float32 {
+			t.Fatalf("expected sample of type %v but got %v",
+				tensor.Float32, sample.Dtype())
+		}
+		for j, v := range sample.Data().([]float32) {
+			sum[j] += float64(v)
+			sumSq[j] += float64(v) * float64(v)
+		}
+	}
+
+	targetMean := []float64{1, -2}
+	targetStd := []float64{1, 3}
+	for j := range sum {
+		m := sum[j] / samples
+		std := math.Sqrt(sumSq[j]/samples - m*m)
+		if math.Abs(m-targetMean[j]) > 0.05*targetStd[j] {
+			t.Errorf("expected mean %v but got %v", targetMean[j], m)
+		}
+		if math.Abs(std-targetStd[j]) > 0.05*targetStd[j] {
+			t.Errorf("expected std %v but got %v", targetStd[j], std)
+		}
+	}
+}
+
+func TestTanhDiagGaussian(t *testing.T) {
+	mean := randTensor([]int{3, 2}, -1, 1)
+	logStd := randTensor([]int{3, 2}, -1, 0.5)
+	d, err := NewTanhDiagGaussian(mean, logStd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	action, pre := d.Sample(rand.NewSource(1))
+	a := action.Data().([]float64)
+	u := pre.Data().([]float64)
+
+	// Compare to the change of variables using atanh, which is accurate
+	// for actions away from the boundary
+	logProb, err := d.LogProb(pre)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := d.Base().LogProb(pre)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetData := append([]float64(nil), base.Data().([]float64)...)
+	for i := range a {
+		if a[i] != math.Tanh(u[i]) || math.Abs(a[i]) >= 1 {
+			t.Errorf("expected action tanh(%v) but got %v", u[i], a[i])
+		}
+		targetData[i/2] -= math.Log(1 - a[i]*a[i])
+	}
+	target := tensor.New(tensor.WithShape(3),
+		tensor.WithBacking(targetData))
+	if !closeTo(logProb, target, 1e-9) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, logProb)
+	}
+
+	// The log-density remains finite for saturated actions
+	saturated := tensor.New(tensor.WithShape(3, 2),
+		tensor.WithBacking([]float64{30, -30, 40, 0, 0, 0}))
+	logProb, err = d.LogProb(saturated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range logProb.Data().([]float64) {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			t.Errorf("expected finite log-density but got %v", v)
+		}
+	}
+}
+
+func TestDiagGaussianGrad(t *testing.T) {
+	mean := randTensor([]int{2, 3}, -1, 1)
+	logStd := randTensor([]int{2, 3}, -1, 0.5)
+	otherMean := randTensor([]int{2, 3}, -1, 1)
+	otherLogStd := randTensor([]int{2, 3}, -1, 0.5)
+	x := randTensor([]int{2, 3}, -2, 2)
+	_, noise := (&DiagGaussian{mean: mean, logStd: logStd}).RSample(
+		rand.NewSource(1))
+
+	checkGrad(t, "rSample",
+		func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+			d, err := NewDiagGaussian(inputs[0], inputs[1])
+			if err != nil {
+				return nil, err
+			}
+			return elementwise(func(v []float64) float64 {
+				return v[0] + math.Exp(v[1])*v[2]
+			}, d.Mean(), d.LogStd(), noise)
+		},
+		func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			d, err := NewDiagGaussian(inputs[0], inputs[1])
+			if err != nil {
+				return nil, err
+			}
+			dMean, dLogStd, err := d.RSampleB(grad, noise)
+			return []tensor.Tensor{dMean, dLogStd}, err
+		},
+		mean, logStd,
+	)
+
+	checkGrad(t, "logProb",
+		func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+			d, err := NewDiagGaussian(inputs[0], inputs[1])
+			if err != nil {
+				return nil, err
+			}
+			return d.LogProb(x)
+		},
+		func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			d, err := NewDiagGaussian(inputs[0], inputs[1])
+			if err != nil {
+				return nil, err
+			}
+			dMean, dLogStd, err := d.LogProbB(grad, x)
+			return []tensor.Tensor{dMean, dLogStd}, err
+		},
+		mean, logStd,
+	)
+
+	checkGrad(t, "entropy",
+		func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+			d, err := NewDiagGaussian(inputs[0], inputs[1])
+			if err != nil {
+				return nil, err
+			}
+			return d.Entropy(), nil
+		},
+		func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			d, err := NewDiagGaussian(inputs[0], inputs[1])
+			if err != nil {
+				return nil, err
+			}
+			dMean, dLogStd, err := d.EntropyB(grad)
+			return []tensor.Tensor{dMean, dLogStd}, err
+		},
+		mean, logStd,
+	)
+
+	checkGrad(t, "kl",
+		func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+			p, err := NewDiagGaussian(inputs[0], inputs[1])
+			if err != nil {
+				return nil, err
+			}
+			q, err := NewDiagGaussian(inputs[2], inputs[3])
+			if err != nil {
+				return nil, err
+			}
+			return p.KL(q)
+		},
+		func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			p, err := NewDiagGaussian(inputs[0], inputs[1])
+			if err != nil {
+				return nil, err
+			}
+			q, err := NewDiagGaussian(inputs[2], inputs[3])
+			if err != nil {
+				return nil, err
+			}
+			dmp, dlp, dmq, dlq, err := p.KLB(grad, q)
+			return []tensor.Tensor{dmp, dlp, dmq, dlq}, err
+		},
+		mean, logStd, otherMean, otherLogStd,
+	)
+
+	// The log-density of a reparameterized squashed sample, as used in
+	// Soft Actor-Critic, differentiated through the sample
+	tanhLogProb := func(inputs []tensor.Tensor) (*TanhDiagGaussian,
+		tensor.Tensor, error) {
+		d, err := NewTanhDiagGaussian(inputs[0], inputs[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		pre, err := elementwise(func(v []float64) float64 {
+			return v[0] + math.Exp(v[1])*v[2]
+		}, inputs[0], inputs[1], noise)
+		return d, pre, err
+	}
+	checkGrad(t, "tanhLogProb",
+		func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+			d, pre, err := tanhLogProb(inputs)
+			if err != nil {
+				return nil, err
+			}
+			return d.LogProb(pre)
+		},
+		func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			d, pre, err := tanhLogProb(inputs)
+			if err != nil {
+				return nil, err
+			}
+			dMean, dLogStd, dPre, err := d.LogProbB(grad, pre)
+			if err != nil {
+				return nil, err
+			}
+			dm, dl, err := d.Base().RSampleB(dPre, noise)
+			if err != nil {
+				return nil, err
+			}
+			dMean, _ = Apply("add", []tensor.Tensor{dMean, dm}, nil)
+			dLogStd, _ = Apply("add", []tensor.Tensor{dLogStd, dl}, nil)
+			return []tensor.Tensor{dMean, dLogStd}, nil
+		},
+		mean, logStd,
+	)
+
+	checkGrad(t, "tanhRSample",
+		func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+			_, pre, err := tanhLogProb(inputs)
+			if err != nil {
+				return nil, err
+			}
+			return Apply("tanh", []tensor.Tensor{pre}, nil)
+		},
+		func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			d, _, err := tanhLogProb(inputs)
+			if err != nil {
+				return nil, err
+			}
+			dMean, dLogStd, err := d.RSampleB(grad, noise)
+			return []tensor.Tensor{dMean, dLogStd}, err
+		},
+		mean, logStd,
+	)
+}
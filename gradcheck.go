@@ -0,0 +1,212 @@
+package top
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gorgonia.org/tensor"
+)
+
+// GradCheckResult reports the largest discrepancies between analytic
+// and numerical gradients found by GradCheck. The relative error of an
+// element is the absolute error divided by the larger of the magnitudes
+// of the analytic and numerical gradients, and is 0 when both are 0.
+type GradCheckResult struct {
+	MaxAbsError float64 // Largest absolute error
+	AbsInput    int     // Input at which the largest absolute error occurs
+	AbsCoords   []int   // Coordinates of the largest absolute error
+
+	MaxRelError float64 // Largest relative error
+	RelInput    int     // Input at which the largest relative error occurs
+	RelCoords   []int   // Coordinates of the largest relative error
+}
+
+// String implements the fmt.Stringer interface
+func (g GradCheckResult) String() string {
+	return fmt.Sprintf("max absolute error %v at input %v %v, max "+
+		"relative error %v at input %v %v", g.MaxAbsError, g.AbsInput,
+		g.AbsCoords, g.MaxRelError, g.RelInput, g.RelCoords)
+}
+
+// GradCheck compares the analytic gradients computed by a backward
+// function against numerical gradients computed by central finite
+// differences of the forward function.
+//
+// The forward function maps the inputs to an output tensor. The
+// backward function vjp computes the vector-Jacobian product of the
+// forward function: given the gradient grad with respect to the output
+// and the inputs, it returns the gradient with respect to each input.
+// GradCheck draws grad from a standard normal distribution using src,
+// and compares the analytic gradients to the numerical gradients of
+// the scalar function <grad, forward(inputs)>, where each element is
+// perturbed by eps in each direction.
+//
+// Only inputs of type tensor.Float64 for which vjp returns a non-nil
+// gradient are checked. Other inputs, such as index tensors, are held
+// fixed.
+func GradCheck(forward func(inputs []tensor.Tensor) (tensor.Tensor, error),
+	vjp func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+		error), inputs []tensor.Tensor, eps float64,
+	src rand.Source) (GradCheckResult, error) {
+	if eps <= 0 {
+		return GradCheckResult{}, fmt.Errorf("gradCheck: eps must be "+
+			"positive but got %v", eps)
+	}
+
+	out, err := forward(inputs)
+	if err != nil {
+		return GradCheckResult{}, fmt.Errorf("gradCheck: %v", err)
+	}
+	if out.Dtype() != tensor.Float64 {
+		return GradCheckResult{}, fmt.Errorf("gradCheck: expected output "+
+			"of type %v but got %v", tensor.Float64, out.Dtype())
+	}
+
+	rng := rand.New(src)
+	g := make([]float64, out.Shape().TotalSize())
+	for i := range g {
+		g[i] = rng.NormFloat64()
+	}
+	grad := newTensor(out.Shape().Clone(), g)
+
+	analytic, err := vjp(grad, inputs)
+	if err != nil {
+		return GradCheckResult{}, fmt.Errorf("gradCheck: %v", err)
+	}
+	if len(analytic) != len(inputs) {
+		return GradCheckResult{}, fmt.Errorf("gradCheck: expected %v "+
+			"gradients but got %v", len(inputs), len(analytic))
+	}
+
+	// objective computes <grad, forward(inputs)>
+	objective := func(inputs []tensor.Tensor) (float64, error) {
+		out, err := forward(inputs)
+		if err != nil {
+			return 0, err
+		}
+		data, err := toFloat64Data(out)
+		if err != nil {
+			return 0, err
+		}
+
+		total := 0.0
+		for i := range data {
+			total += g[i] * data[i]
+		}
+		return total, nil
+	}
+
+	var result GradCheckResult
+	perturbed := make([]tensor.Tensor, len(inputs))
+	copy(perturbed, inputs)
+	for i, in := range inputs {
+		if in.Dtype() != tensor.Float64 || analytic[i] == nil {
+			continue
+		}
+		if !analytic[i].Shape().Eq(in.Shape()) {
+			return GradCheckResult{}, fmt.Errorf("gradCheck: expected "+
+				"gradient of input %v to have shape %v but got %v", i,
+				in.Shape(), analytic[i].Shape())
+		}
+
+		a, err := toFloat64Data(analytic[i])
+		if err != nil {
+			return GradCheckResult{}, fmt.Errorf("gradCheck: gradient of "+
+				"input %v: %v", i, err)
+		}
+		inData, _ := float64Data(in)
+		data := make([]float64, len(inData))
+		copy(data, inData)
+
+		for j := range data {
+			value := data[j]
+
+			data[j] = value + eps
+			perturbed[i] = newTensor(in.Shape().Clone(), data)
+			plus, err := objective(perturbed)
+			if err != nil {
+				return GradCheckResult{}, fmt.Errorf("gradCheck: %v", err)
+			}
+
+			data[j] = value - eps
+			perturbed[i] = newTensor(in.Shape().Clone(), data)
+			minus, err := objective(perturbed)
+			if err != nil {
+				return GradCheckResult{}, fmt.Errorf("gradCheck: %v", err)
+			}
+			data[j] = value
+
+			numeric := (plus - minus) / (2 * eps)
+			absErr := math.Abs(a[j] - numeric)
+			relErr := 0.0
+			if scale := math.Max(math.Abs(a[j]), math.Abs(numeric)); scale > 0 {
+				relErr = absErr / scale
+			}
+
+			if absErr > result.MaxAbsError || result.AbsCoords == nil {
+				result.MaxAbsError = absErr
+				result.AbsInput = i
+				result.AbsCoords = unravelIndex(j, in.Shape())
+			}
+			if relErr > result.MaxRelError || result.RelCoords == nil {
+				result.MaxRelError = relErr
+				result.RelInput = i
+				result.RelCoords = unravelIndex(j, in.Shape())
+			}
+		}
+		perturbed[i] = in
+	}
+
+	if result.AbsCoords == nil {
+		return GradCheckResult{}, fmt.Errorf("gradCheck: no float64 inputs " +
+			"with gradients to check")
+	}
+	return result, nil
+}
+
+// GradCheckOp runs GradCheck on the registered operation called name,
+// comparing its vector-Jacobian product to numerical gradients of its
+// forward function with the argument inputs and attributes. See
+// GradCheck for more details.
+func GradCheckOp(name string, inputs []tensor.Tensor, attrs Attrs, eps float64,
+	src rand.Source) (GradCheckResult, error) {
+	op, ok := Lookup(name)
+	if !ok {
+		return GradCheckResult{}, fmt.Errorf("gradCheckOp: unknown "+
+			"operation %q", name)
+	}
+	if !op.Differentiable() {
+		return GradCheckResult{}, fmt.Errorf("gradCheckOp: operation %q is "+
+			"not differentiable", name)
+	}
+
+	forward := func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+		return Apply(name, inputs, attrs)
+	}
+	vjp := func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+		error) {
+		out, err := op.Forward(inputs, attrs)
+		if err != nil {
+			return nil, err
+		}
+		return op.VJP(grad, inputs, out, attrs)
+	}
+
+	result, err := GradCheck(forward, vjp, inputs, eps, src)
+	if err != nil {
+		return GradCheckResult{}, fmt.Errorf("gradCheckOp: %v: %v", name, err)
+	}
+	return result, nil
+}
+
+// unravelIndex returns the coordinates of the element at the argument
+// row-major index of a tensor of the argument shape
+func unravelIndex(index int, shape tensor.Shape) []int {
+	coords := make([]int, len(shape))
+	for i := len(shape) - 1; i >= 0; i-- {
+		coords[i] = index % shape[i]
+		index /= shape[i]
+	}
+	return coords
+}
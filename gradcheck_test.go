@@ -0,0 +1,272 @@
+package top
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"gorgonia.org/tensor"
+)
+
+const (
+	gradCheckEps = 1e-6 // Finite difference step used in tests
+	gradCheckTol = 1e-5 // Largest absolute error allowed in tests
+)
+
+// checkGrad runs GradCheck and reports an error if the maximum
+// absolute error is too large
+func checkGrad(t *testing.T, name string,
+	forward func([]tensor.Tensor) (tensor.Tensor, error),
+	vjp func(tensor.Tensor, []tensor.Tensor) ([]tensor.Tensor, error),
+	inputs ...tensor.Tensor) {
+	t.Helper()
+
+	src := rand.NewSource(time.Now().UnixNano())
+	result, err := GradCheck(forward, vjp, inputs, gradCheckEps, src)
+	if err != nil {
+		t.Errorf("%v: %v", name, err)
+	} else if result.MaxAbsError > gradCheckTol {
+		t.Errorf("%v: %v", name, result)
+	}
+}
+
+// TestGradCheckOps checks the vector-Jacobian product of each
+// differentiable builtin operation in the registry
+func TestGradCheckOps(t *testing.T) {
+	src := rand.NewSource(time.Now().UnixNano())
+	for _, c := range registryCases() {
+		result, err := GradCheckOp(c.name, c.inputs, c.attrs, gradCheckEps,
+			src)
+		if err != nil {
+			t.Error(err)
+		} else if result.MaxAbsError > gradCheckTol {
+			t.Errorf("%v: %v", c.name, result)
+		}
+	}
+}
+
+// TestGradCheckBackward checks each of the backward functions of the
+// package against its forward function
+func TestGradCheckBackward(t *testing.T) {
+	x := randTensor([]int{3, 4}, -2, 2)
+	y := randTensor([]int{4}, -2, 2)
+	cond := tensor.NewDense(
+		tensor.Bool,
+		[]int{3, 1},
+		tensor.WithBacking([]bool{true, false, true}),
+	)
+	ids := tensor.NewDense(
+		tensor.Uint8,
+		[]int{3},
+		tensor.WithBacking([]uint8{0, 2, 2}),
+	)
+
+	// ClampB returns a mask which is multiplied by the upstream gradient
+	checkGrad(t, "ClampB",
+		func(in []tensor.Tensor) (tensor.Tensor, error) {
+			return Clamp(in[0], -1.0, 1.0)
+		},
+		func(grad tensor.Tensor, in []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			mask, err := ClampB(in[0], -1.0, 1.0)
+			if err != nil {
+				return nil, err
+			}
+			dx, err := tensor.Mul(grad, mask)
+			return []tensor.Tensor{dx}, err
+		},
+		x,
+	)
+
+	// GatherB returns a mask which is the gradient of the sum of the
+	// output of Gather when no element is gathered more than once
+	unique := tensor.NewDense(
+		tensor.Int,
+		[]int{3, 2},
+		tensor.WithBacking([]int{0, 3, 2, 1, 1, 0}),
+	)
+	checkGrad(t, "GatherB",
+		func(in []tensor.Tensor) (tensor.Tensor, error) {
+			out, err := Gather(in[0], 1, unique)
+			if err != nil {
+				return nil, err
+			}
+			return sumAlong(out, NoAxis, false, false)
+		},
+		func(grad tensor.Tensor, in []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			mask, err := GatherB(in[0], 1, unique)
+			if err != nil {
+				return nil, err
+			}
+			dx, err := tensor.Mul(mask, grad.ScalarValue())
+			return []tensor.Tensor{dx}, err
+		},
+		x,
+	)
+
+	checkGrad(t, "ScatterAdd",
+		func(in []tensor.Tensor) (tensor.Tensor, error) {
+			return Gather(in[0], 0, ids3x4())
+		},
+		func(grad tensor.Tensor, in []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			dx, err := ScatterAdd(grad, 0, ids3x4(), in[0].Shape())
+			return []tensor.Tensor{dx}, err
+		},
+		x,
+	)
+
+	checkGrad(t, "WhereB",
+		func(in []tensor.Tensor) (tensor.Tensor, error) {
+			return Where(cond, in[0], in[1])
+		},
+		func(grad tensor.Tensor, in []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			dx, dy, err := WhereB(grad, cond, in[0].Shape(), in[1].Shape())
+			return []tensor.Tensor{dx, dy}, err
+		},
+		x, y,
+	)
+
+	for _, keepdims := range []bool{true, false} {
+		keepdims := keepdims
+		checkGrad(t, "MaxAlongB",
+			func(in []tensor.Tensor) (tensor.Tensor, error) {
+				values, _, err := MaxAlong(in[0], 1, keepdims)
+				return values, err
+			},
+			func(grad tensor.Tensor, in []tensor.Tensor) ([]tensor.Tensor,
+				error) {
+				_, indices, err := MaxAlong(in[0], 1, keepdims)
+				if err != nil {
+					return nil, err
+				}
+				dx, err := MaxAlongB(grad, indices, in[0].Shape(), 1)
+				return []tensor.Tensor{dx}, err
+			},
+			x,
+		)
+	}
+
+	segments := []struct {
+		name     string
+		forward  func(tensor.Tensor, tensor.Tensor) (tensor.Tensor, error)
+		backward func(grad, data, output tensor.Tensor) (tensor.Tensor, error)
+	}{
+		{"SegmentSumB", SegmentSum,
+			func(grad, data, output tensor.Tensor) (tensor.Tensor, error) {
+				return SegmentSumB(grad, ids)
+			}},
+		{"SegmentMeanB", SegmentMean,
+			func(grad, data, output tensor.Tensor) (tensor.Tensor, error) {
+				return SegmentMeanB(grad, ids)
+			}},
+		{"SegmentMaxB", SegmentMax,
+			func(grad, data, output tensor.Tensor) (tensor.Tensor, error) {
+				return SegmentMaxB(grad, data, ids, output)
+			}},
+		{"SegmentMinB", SegmentMin,
+			func(grad, data, output tensor.Tensor) (tensor.Tensor, error) {
+				return SegmentMinB(grad, data, ids, output)
+			}},
+	}
+	for _, s := range segments {
+		s := s
+		checkGrad(t, s.name,
+			func(in []tensor.Tensor) (tensor.Tensor, error) {
+				return s.forward(in[0], ids)
+			},
+			func(grad tensor.Tensor, in []tensor.Tensor) ([]tensor.Tensor,
+				error) {
+				out, err := s.forward(in[0], ids)
+				if err != nil {
+					return nil, err
+				}
+				dx, err := s.backward(grad, in[0], out)
+				return []tensor.Tensor{dx}, err
+			},
+			x,
+		)
+	}
+}
+
+// ids3x4 returns indices for gathering from a tensor of shape (3, 4)
+// along the first axis, with repeated indices
+func ids3x4() tensor.Tensor {
+	return tensor.NewDense(
+		tensor.Int,
+		[]int{2, 4},
+		tensor.WithBacking([]int{2, 2, 0, 1, 2, 0, 0, 1}),
+	)
+}
+
+// TestGradCheckDetectsMask tests that GradCheck detects that the mask
+// returned by GatherB is not the gradient of Gather when elements are
+// gathered more than once
+func TestGradCheckDetectsMask(t *testing.T) {
+	x := randTensor([]int{3, 4}, -2, 2)
+
+	result, err := GradCheck(
+		func(in []tensor.Tensor) (tensor.Tensor, error) {
+			out, err := Gather(in[0], 0, ids3x4())
+			if err != nil {
+				return nil, err
+			}
+			return sumAlong(out, NoAxis, false, false)
+		},
+		func(grad tensor.Tensor, in []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			mask, err := GatherB(in[0], 0, ids3x4())
+			if err != nil {
+				return nil, err
+			}
+			dx, err := tensor.Mul(mask, grad.ScalarValue())
+			return []tensor.Tensor{dx}, err
+		},
+		[]tensor.Tensor{x},
+		gradCheckEps,
+		rand.NewSource(1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Element (2, 0) is gathered twice but has a mask value of 1
+	if result.MaxRelError < 0.4 {
+		t.Errorf("expected GradCheck to detect incorrect gradient: %v",
+			result)
+	}
+	if result.RelInput != 0 || len(result.RelCoords) != 2 {
+		t.Errorf("expected coordinates of error in input 0 but got "+
+			"input %v %v", result.RelInput, result.RelCoords)
+	}
+}
+
+func TestGradCheckErrors(t *testing.T) {
+	forward := func(in []tensor.Tensor) (tensor.Tensor, error) {
+		return in[0], nil
+	}
+	vjp := func(grad tensor.Tensor, in []tensor.Tensor) ([]tensor.Tensor,
+		error) {
+		return []tensor.Tensor{grad}, nil
+	}
+	src := rand.NewSource(1)
+
+	if _, err := GradCheck(forward, vjp, []tensor.Tensor{randTensor([]int{2},
+		0, 1)}, 0, src); err == nil {
+		t.Error("expected error for non-positive eps")
+	}
+
+	ints := tensor.NewDense(tensor.Int, []int{2},
+		tensor.WithBacking([]int{1, 2}))
+	if _, err := GradCheck(forward, vjp, []tensor.Tensor{ints},
+		gradCheckEps, src); err == nil {
+		t.Error("expected error for non-float64 output")
+	}
+
+	if _, err := GradCheckOp("argsort", []tensor.Tensor{randTensor([]int{2},
+		0, 1)}, Attrs{"axis": 0}, gradCheckEps, src); err == nil {
+		t.Error("expected error for non-differentiable operation")
+	}
+}
@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"fmt"
+	"hash"
+
+	"github.com/chewxy/hm"
+	"github.com/samuelfneumann/top"
+	G "gorgonia.org/gorgonia"
+	"gorgonia.org/tensor"
+)
+
+// ArgsortOp is a gorgonia Op which computes the indices which sort its
+// input along an axis using top.Argsort. The output is a tensor of
+// type tensor.Int. ArgsortOp is not differentiable.
+type ArgsortOp struct {
+	axis int
+	dims int
+}
+
+// NewArgsortOp returns a new ArgsortOp which sorts along axis of a
+// tensor with dims dimensions
+func NewArgsortOp(axis, dims int) *ArgsortOp {
+	return &ArgsortOp{axis: axis, dims: dims}
+}
+
+// ArgsortNode computes the indices which sort x along axis. See
+// top.Argsort for more details.
+func ArgsortNode(x *G.Node, axis int) (*G.Node, error) {
+	return G.ApplyOp(NewArgsortOp(axis, x.Dims()), x)
+}
+
+// Arity implements the gorgonia.Op interface
+func (op *ArgsortOp) Arity() int { return 1 }
+
+// Type implements the gorgonia.Op interface
+func (op *ArgsortOp) Type() hm.Type {
+	x := tensorType(op.dims, hm.TypeVariable('a'))
+	return hm.NewFnType(x, tensorType(op.dims, tensor.Int))
+}
+
+// InferShape implements the gorgonia.Op interface
+func (op *ArgsortOp) InferShape(inputs ...G.DimSizer) (tensor.Shape, error) {
+	return sameShape(op, inputs...)
+}
+
+// Do implements the gorgonia.Op interface
+func (op *ArgsortOp) Do(values ...G.Value) (G.Value, error) {
+	if err := checkArity(op, len(values)); err != nil {
+		return nil, err
+	}
+	in, err := toTensor(values[0])
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+
+	out, err := top.Argsort(in, op.axis)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	return out, nil
+}
+
+// ReturnsPtr implements the gorgonia.Op interface
+func (op *ArgsortOp) ReturnsPtr() bool { return false }
+
+// CallsExtern implements the gorgonia.Op interface
+func (op *ArgsortOp) CallsExtern() bool { return false }
+
+// OverwritesInput implements the gorgonia.Op interface
+func (op *ArgsortOp) OverwritesInput() int { return -1 }
+
+// WriteHash implements the gorgonia.Op interface
+func (op *ArgsortOp) WriteHash(h hash.Hash) { writeHash(h, op) }
+
+// Hashcode implements the gorgonia.Op interface
+func (op *ArgsortOp) Hashcode() uint32 { return hashcode(op) }
+
+// String implements the fmt.Stringer interface
+func (op *ArgsortOp) String() string {
+	return fmt.Sprintf("Argsort{axis=%v}", op.axis)
+}
+
+// DiffWRT implements the gorgonia.SDOp interface
+func (op *ArgsortOp) DiffWRT(inputs int) []bool { return []bool{false} }
+
+// SymDiff implements the gorgonia.SDOp interface
+func (op *ArgsortOp) SymDiff(inputs G.Nodes, output,
+	grad *G.Node) (G.Nodes, error) {
+	return nil, fmt.Errorf("%v: operation is not differentiable", op)
+}
@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"fmt"
+	"hash"
+
+	"github.com/chewxy/hm"
+	"github.com/samuelfneumann/top"
+	G "gorgonia.org/gorgonia"
+	"gorgonia.org/tensor"
+)
+
+// ClampOp is a gorgonia Op which clamps its input to the range
+// [min, max] using top.Clamp. The gradient of the input is the
+// gradient of the output masked by top.ClampB, so that elements
+// outside of [min, max] have a gradient of 0.
+type ClampOp struct {
+	min, max interface{}
+	dims     int
+}
+
+// NewClampOp returns a new ClampOp which clamps a tensor with dims
+// dimensions to the range [min, max]. The types of min and max must
+// be legal for top.Clamp with the input to the Op.
+func NewClampOp(min, max interface{}, dims int) *ClampOp {
+	return &ClampOp{min: min, max: max, dims: dims}
+}
+
+// ClampNode clamps x to the range [min, max]. See top.Clamp for more
+// details.
+func ClampNode(x *G.Node, min, max interface{}) (*G.Node, error) {
+	return G.ApplyOp(NewClampOp(min, max, x.Dims()), x)
+}
+
+// Arity implements the gorgonia.Op interface
+func (op *ClampOp) Arity() int { return 1 }
+
+// Type implements the gorgonia.Op interface
+func (op *ClampOp) Type() hm.Type {
+	x := tensorType(op.dims, hm.TypeVariable('a'))
+	return hm.NewFnType(x, x)
+}
+
+// InferShape implements the gorgonia.Op interface
+func (op *ClampOp) InferShape(inputs ...G.DimSizer) (tensor.Shape, error) {
+	return sameShape(op, inputs...)
+}
+
+// Do implements the gorgonia.Op interface
+func (op *ClampOp) Do(values ...G.Value) (G.Value, error) {
+	if err := checkArity(op, len(values)); err != nil {
+		return nil, err
+	}
+	in, err := toTensor(values[0])
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+
+	out, err := top.Clamp(in, op.min, op.max)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	return out, nil
+}
+
+// ReturnsPtr implements the gorgonia.Op interface
+func (op *ClampOp) ReturnsPtr() bool { return false }
+
+// CallsExtern implements the gorgonia.Op interface
+func (op *ClampOp) CallsExtern() bool { return false }
+
+// OverwritesInput implements the gorgonia.Op interface
+func (op *ClampOp) OverwritesInput() int { return -1 }
+
+// WriteHash implements the gorgonia.Op interface
+func (op *ClampOp) WriteHash(h hash.Hash) { writeHash(h, op) }
+
+// Hashcode implements the gorgonia.Op interface
+func (op *ClampOp) Hashcode() uint32 { return hashcode(op) }
+
+// String implements the fmt.Stringer interface
+func (op *ClampOp) String() string {
+	return fmt.Sprintf("Clamp{min=%v, max=%v}", op.min, op.max)
+}
+
+// DiffWRT implements the gorgonia.SDOp interface
+func (op *ClampOp) DiffWRT(inputs int) []bool { return []bool{true} }
+
+// SymDiff implements the gorgonia.SDOp interface
+func (op *ClampOp) SymDiff(inputs G.Nodes, output,
+	grad *G.Node) (G.Nodes, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+
+	mask, err := ClampBNode(inputs[0], op.min, op.max)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	dx, err := G.HadamardProd(grad, mask)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	return G.Nodes{dx}, nil
+}
+
+// DoDiff implements the gorgonia.ADOp interface
+func (op *ClampOp) DoDiff(ctx G.ExecutionContext, inputs G.Nodes,
+	output *G.Node) error {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return err
+	}
+
+	outGrad, err := output.Grad()
+	if err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+	in, err := toTensors(outGrad, inputs[0].Value())
+	if err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+
+	mask, err := top.ClampB(in[1], op.min, op.max)
+	if err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+	dx, err := tensor.Mul(in[0], mask)
+	if err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+	if err := accumulateGrad(inputs[0], dx); err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+	return nil
+}
+
+// ClampBOp is a gorgonia Op which computes the mask returned by
+// top.ClampB, which is 1 where its input lies within [min, max] and 0
+// elsewhere. ClampBOp is not differentiable.
+type ClampBOp struct {
+	min, max interface{}
+	dims     int
+}
+
+// NewClampBOp returns a new ClampBOp for a tensor with dims dimensions
+// and the range [min, max]
+func NewClampBOp(min, max interface{}, dims int) *ClampBOp {
+	return &ClampBOp{min: min, max: max, dims: dims}
+}
+
+// ClampBNode computes the backward pass mask of clamping x to the
+// range [min, max]. See top.ClampB for more details.
+func ClampBNode(x *G.Node, min, max interface{}) (*G.Node, error) {
+	return G.ApplyOp(NewClampBOp(min, max, x.Dims()), x)
+}
+
+// Arity implements the gorgonia.Op interface
+func (op *ClampBOp) Arity() int { return 1 }
+
+// Type implements the gorgonia.Op interface
+func (op *ClampBOp) Type() hm.Type {
+	x := tensorType(op.dims, hm.TypeVariable('a'))
+	return hm.NewFnType(x, x)
+}
+
+// InferShape implements the gorgonia.Op interface
+func (op *ClampBOp) InferShape(inputs ...G.DimSizer) (tensor.Shape, error) {
+	return sameShape(op, inputs...)
+}
+
+// Do implements the gorgonia.Op interface
+func (op *ClampBOp) Do(values ...G.Value) (G.Value, error) {
+	if err := checkArity(op, len(values)); err != nil {
+		return nil, err
+	}
+	in, err := toTensor(values[0])
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+
+	out, err := top.ClampB(in, op.min, op.max)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	return out, nil
+}
+
+// ReturnsPtr implements the gorgonia.Op interface
+func (op *ClampBOp) ReturnsPtr() bool { return false }
+
+// CallsExtern implements the gorgonia.Op interface
+func (op *ClampBOp) CallsExtern() bool { return false }
+
+// OverwritesInput implements the gorgonia.Op interface
+func (op *ClampBOp) OverwritesInput() int { return -1 }
+
+// WriteHash implements the gorgonia.Op interface
+func (op *ClampBOp) WriteHash(h hash.Hash) { writeHash(h, op) }
+
+// Hashcode implements the gorgonia.Op interface
+func (op *ClampBOp) Hashcode() uint32 { return hashcode(op) }
+
+// String implements the fmt.Stringer interface
+func (op *ClampBOp) String() string {
+	return fmt.Sprintf("ClampB{min=%v, max=%v}", op.min, op.max)
+}
+
+// DiffWRT implements the gorgonia.SDOp interface
+func (op *ClampBOp) DiffWRT(inputs int) []bool { return []bool{false} }
+
+// SymDiff implements the gorgonia.SDOp interface
+func (op *ClampBOp) SymDiff(inputs G.Nodes, output,
+	grad *G.Node) (G.Nodes, error) {
+	return nil, fmt.Errorf("%v: operation is not differentiable", op)
+}
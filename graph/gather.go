@@ -0,0 +1,296 @@
+package graph
+
+import (
+	"fmt"
+	"hash"
+
+	"github.com/chewxy/hm"
+	"github.com/samuelfneumann/top"
+	G "gorgonia.org/gorgonia"
+	"gorgonia.org/tensor"
+)
+
+// GatherOp is a gorgonia Op which gathers values along an axis using
+// top.Gather. The first input is the tensor to gather from and the
+// second input is the integer tensor of indices. The gradient with
+// respect to the first input is computed by scatter-adding the
+// gradient of the output back to the gathered positions using
+// top.ScatterAdd. Indices are not differentiable.
+type GatherOp struct {
+	axis int
+	dims int
+}
+
+// NewGatherOp returns a new GatherOp which gathers along axis of a
+// tensor with dims dimensions
+func NewGatherOp(axis, dims int) *GatherOp {
+	return &GatherOp{axis: axis, dims: dims}
+}
+
+// GatherNode gathers values of x along axis using the indices in idx.
+// The node x must be of type float64, float32, or int. See top.Gather
+// for more details.
+func GatherNode(x *G.Node, axis int, idx *G.Node) (*G.Node, error) {
+	op := NewGatherOp(axis, x.Dims())
+	if err := op.checkDtype(x.Dtype()); err != nil {
+		return nil, err
+	}
+	return G.ApplyOp(op, x, idx)
+}
+
+// checkDtype returns an error if the GatherOp cannot gather from a
+// tensor of type dt. Since top.Gather returns an int tensor when
+// gathering from any integer type, only the types which keep their
+// type through top.Gather are supported, so that the output has the
+// type inferred by the GatherOp.
+func (op *GatherOp) checkDtype(dt tensor.Dtype) error {
+	switch dt {
+	case tensor.Float64, tensor.Float32, tensor.Int:
+		return nil
+
+	default:
+		return fmt.Errorf("%v: expected input of type %v, %v, or %v but "+
+			"got %v", op, tensor.Float64, tensor.Float32, tensor.Int, dt)
+	}
+}
+
+// Arity implements the gorgonia.Op interface
+func (op *GatherOp) Arity() int { return 2 }
+
+// Type implements the gorgonia.Op interface
+func (op *GatherOp) Type() hm.Type {
+	// Gorgonia reserves the type variable b for the return type during
+	// type inference, so indices use the type variable i
+	a := hm.TypeVariable('a')
+	i := hm.TypeVariable('i')
+	x := tensorType(op.dims, a)
+	return hm.NewFnType(x, tensorType(op.dims, i), x)
+}
+
+// InferShape implements the gorgonia.Op interface. The output has the
+// same shape as the indices.
+func (op *GatherOp) InferShape(inputs ...G.DimSizer) (tensor.Shape, error) {
+	if len(inputs) != op.Arity() {
+		return nil, fmt.Errorf("%v: expected %v inputs but got %v", op,
+			op.Arity(), len(inputs))
+	}
+
+	idxShape, err := shapeOf(inputs[1])
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	return idxShape.Clone(), nil
+}
+
+// Do implements the gorgonia.Op interface
+func (op *GatherOp) Do(values ...G.Value) (G.Value, error) {
+	if err := checkArity(op, len(values)); err != nil {
+		return nil, err
+	}
+	in, err := toTensors(values...)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	if err := op.checkDtype(in[0].Dtype()); err != nil {
+		return nil, err
+	}
+
+	out, err := top.Gather(in[0], op.axis, in[1])
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	return out, nil
+}
+
+// ReturnsPtr implements the gorgonia.Op interface
+func (op *GatherOp) ReturnsPtr() bool { return false }
+
+// CallsExtern implements the gorgonia.Op interface
+func (op *GatherOp) CallsExtern() bool { return false }
+
+// OverwritesInput implements the gorgonia.Op interface
+func (op *GatherOp) OverwritesInput() int { return -1 }
+
+// WriteHash implements the gorgonia.Op interface
+func (op *GatherOp) WriteHash(h hash.Hash) { writeHash(h, op) }
+
+// Hashcode implements the gorgonia.Op interface
+func (op *GatherOp) Hashcode() uint32 { return hashcode(op) }
+
+// String implements the fmt.Stringer interface
+func (op *GatherOp) String() string {
+	return fmt.Sprintf("Gather{axis=%v, dims=%v}", op.axis, op.dims)
+}
+
+// DiffWRT implements the gorgonia.SDOp interface
+func (op *GatherOp) DiffWRT(inputs int) []bool { return []bool{true, false} }
+
+// SymDiff implements the gorgonia.SDOp interface
+func (op *GatherOp) SymDiff(inputs G.Nodes, output,
+	grad *G.Node) (G.Nodes, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+
+	x, idx := inputs[0], inputs[1]
+	dx, err := ScatterAddNode(grad, op.axis, idx, x.Shape())
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	return G.Nodes{dx, nil}, nil
+}
+
+// DoDiff implements the gorgonia.ADOp interface
+func (op *GatherOp) DoDiff(ctx G.ExecutionContext, inputs G.Nodes,
+	output *G.Node) error {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return err
+	}
+
+	outGrad, err := output.Grad()
+	if err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+	in, err := toTensors(outGrad, inputs[1].Value())
+	if err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+
+	dx, err := top.ScatterAdd(in[0], op.axis, in[1], inputs[0].Shape())
+	if err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+	if err := accumulateGrad(inputs[0], dx); err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+	return nil
+}
+
+// ScatterAddOp is a gorgonia Op which scatter-adds values along an axis
+// into a tensor of zeros of a given shape using top.ScatterAdd. The
+// first input is the tensor of values to scatter and the second input
+// is the integer tensor of indices. ScatterAddOp is the adjoint of
+// GatherOp, and its gradient with respect to the first input is
+// computed by gathering the gradient of the output. Indices are not
+// differentiable.
+type ScatterAddOp struct {
+	axis  int
+	shape tensor.Shape
+}
+
+// NewScatterAddOp returns a new ScatterAddOp which scatters along axis
+// into a tensor of the argument shape
+func NewScatterAddOp(axis int, shape tensor.Shape) *ScatterAddOp {
+	return &ScatterAddOp{axis: axis, shape: shape.Clone()}
+}
+
+// ScatterAddNode scatter-adds the values of src along axis at the
+// indices in idx into a tensor of zeros of the argument shape. See
+// top.ScatterAdd for more details.
+func ScatterAddNode(src *G.Node, axis int, idx *G.Node,
+	shape tensor.Shape) (*G.Node, error) {
+	return G.ApplyOp(NewScatterAddOp(axis, shape), src, idx)
+}
+
+// Arity implements the gorgonia.Op interface
+func (op *ScatterAddOp) Arity() int { return 2 }
+
+// Type implements the gorgonia.Op interface
+func (op *ScatterAddOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	i := hm.TypeVariable('i')
+	src := tensorType(len(op.shape), a)
+	return hm.NewFnType(src, tensorType(len(op.shape), i), src)
+}
+
+// InferShape implements the gorgonia.Op interface
+func (op *ScatterAddOp) InferShape(inputs ...G.DimSizer) (tensor.Shape,
+	error) {
+	if len(inputs) != op.Arity() {
+		return nil, fmt.Errorf("%v: expected %v inputs but got %v", op,
+			op.Arity(), len(inputs))
+	}
+	return op.shape.Clone(), nil
+}
+
+// Do implements the gorgonia.Op interface
+func (op *ScatterAddOp) Do(values ...G.Value) (G.Value, error) {
+	if err := checkArity(op, len(values)); err != nil {
+		return nil, err
+	}
+	in, err := toTensors(values...)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+
+	out, err := top.ScatterAdd(in[0], op.axis, in[1], op.shape)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	return out, nil
+}
+
+// ReturnsPtr implements the gorgonia.Op interface
+func (op *ScatterAddOp) ReturnsPtr() bool { return false }
+
+// CallsExtern implements the gorgonia.Op interface
+func (op *ScatterAddOp) CallsExtern() bool { return false }
+
+// OverwritesInput implements the gorgonia.Op interface
+func (op *ScatterAddOp) OverwritesInput() int { return -1 }
+
+// WriteHash implements the gorgonia.Op interface
+func (op *ScatterAddOp) WriteHash(h hash.Hash) { writeHash(h, op) }
+
+// Hashcode implements the gorgonia.Op interface
+func (op *ScatterAddOp) Hashcode() uint32 { return hashcode(op) }
+
+// String implements the fmt.Stringer interface
+func (op *ScatterAddOp) String() string {
+	return fmt.Sprintf("ScatterAdd{axis=%v, shape=%v}", op.axis, op.shape)
+}
+
+// DiffWRT implements the gorgonia.SDOp interface
+func (op *ScatterAddOp) DiffWRT(inputs int) []bool {
+	return []bool{true, false}
+}
+
+// SymDiff implements the gorgonia.SDOp interface
+func (op *ScatterAddOp) SymDiff(inputs G.Nodes, output,
+	grad *G.Node) (G.Nodes, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+
+	dsrc, err := GatherNode(grad, op.axis, inputs[1])
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	return G.Nodes{dsrc, nil}, nil
+}
+
+// DoDiff implements the gorgonia.ADOp interface
+func (op *ScatterAddOp) DoDiff(ctx G.ExecutionContext, inputs G.Nodes,
+	output *G.Node) error {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return err
+	}
+
+	outGrad, err := output.Grad()
+	if err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+	in, err := toTensors(outGrad, inputs[1].Value())
+	if err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+
+	dsrc, err := top.Gather(in[0], op.axis, in[1])
+	if err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+	if err := accumulateGrad(inputs[0], dsrc); err != nil {
+		return fmt.Errorf("%v: %v", op, err)
+	}
+	return nil
+}
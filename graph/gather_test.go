@@ -0,0 +1,251 @@
+package graph
+
+import (
+	"testing"
+
+	G "gorgonia.org/gorgonia"
+	"gorgonia.org/tensor"
+)
+
+func TestGatherNodeGrad(t *testing.T) {
+	g := G.NewGraph()
+	x := G.NewMatrix(
+		g,
+		tensor.Float64,
+		G.WithShape(2, 3),
+		G.WithName("x"),
+		G.WithValue(tensor.NewDense(
+			tensor.Float64,
+			[]int{2, 3},
+			tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6}),
+		)),
+	)
+	idx := G.NewMatrix(
+		g,
+		tensor.Int,
+		G.WithShape(2, 2),
+		G.WithName("idx"),
+		G.WithValue(tensor.NewDense(
+			tensor.Int,
+			[]int{2, 2},
+			tensor.WithBacking([]int{0, 0, 2, 1}),
+		)),
+	)
+
+	gathered, err := GatherNode(x, 1, idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cost, err := G.Sum(gathered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grads, err := G.Grad(cost, x)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm := G.NewTapeMachine(g)
+	defer vm.Close()
+	if err := vm.RunAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 2},
+		tensor.WithBacking([]float64{1, 1, 6, 5}),
+	)
+	if !gathered.Value().(tensor.Tensor).Eq(out) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", out, gathered.Value())
+	}
+
+	grad := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{2, 0, 0, 0, 1, 1}),
+	)
+	if !grads[0].Value().(tensor.Tensor).Eq(grad) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", grad, grads[0].Value())
+	}
+}
+
+func TestClampNodeGrad(t *testing.T) {
+	g := G.NewGraph()
+	x := G.NewVector(
+		g,
+		tensor.Float64,
+		G.WithShape(4),
+		G.WithName("x"),
+		G.WithValue(tensor.NewDense(
+			tensor.Float64,
+			[]int{4},
+			tensor.WithBacking([]float64{-2, -0.5, 0.5, 2}),
+		)),
+	)
+
+	clamped, err := ClampNode(x, -1.0, 1.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cost, err := G.Sum(clamped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grads, err := G.Grad(cost, x)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm := G.NewTapeMachine(g)
+	defer vm.Close()
+	if err := vm.RunAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	grad := tensor.NewDense(
+		tensor.Float64,
+		[]int{4},
+		tensor.WithBacking([]float64{0, 1, 1, 0}),
+	)
+	if !grads[0].Value().(tensor.Tensor).Eq(grad) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", grad, grads[0].Value())
+	}
+}
+
+func TestGatherNodeLispMachine(t *testing.T) {
+	g := G.NewGraph()
+	x := G.NewVector(
+		g,
+		tensor.Float64,
+		G.WithShape(3),
+		G.WithName("x"),
+		G.WithValue(tensor.NewDense(
+			tensor.Float64,
+			[]int{3},
+			tensor.WithBacking([]float64{1, 2, 3}),
+		)),
+	)
+	idx := G.NewVector(
+		g,
+		tensor.Int,
+		G.WithShape(4),
+		G.WithName("idx"),
+		G.WithValue(tensor.NewDense(
+			tensor.Int,
+			[]int{4},
+			tensor.WithBacking([]int{2, 2, 0, 2}),
+		)),
+	)
+
+	gathered, err := GatherNode(x, 0, idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := G.Sum(gathered); err != nil {
+		t.Fatal(err)
+	}
+
+	vm := G.NewLispMachine(g)
+	defer vm.Close()
+	if err := vm.RunAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	xGrad, err := x.Grad()
+	if err != nil {
+		t.Fatal(err)
+	}
+	grad := tensor.NewDense(
+		tensor.Float64,
+		[]int{3},
+		tensor.WithBacking([]float64{1, 0, 3}),
+	)
+	if !xGrad.(tensor.Tensor).Eq(grad) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", grad, xGrad)
+	}
+}
+
+// TestGatherNodeDtype tests that GatherNode rejects integer types
+// which top.Gather would convert to int
+func TestGatherNodeDtype(t *testing.T) {
+	g := G.NewGraph()
+	x := G.NewVector(
+		g,
+		tensor.Int32,
+		G.WithShape(3),
+		G.WithName("x"),
+		G.WithValue(tensor.NewDense(
+			tensor.Int32,
+			[]int{3},
+			tensor.WithBacking([]int32{1, 2, 3}),
+		)),
+	)
+	idx := G.NewVector(
+		g,
+		tensor.Int,
+		G.WithShape(2),
+		G.WithName("idx"),
+		G.WithValue(tensor.NewDense(
+			tensor.Int,
+			[]int{2},
+			tensor.WithBacking([]int{2, 0}),
+		)),
+	)
+
+	if _, err := GatherNode(x, 0, idx); err == nil {
+		t.Error("expected error for input of type int32")
+	}
+	if _, err := NewGatherOp(0, 1).Do(x.Value(), idx.Value()); err == nil {
+		t.Error("expected error for input of type int32")
+	}
+}
+
+func TestGatherOpHash(t *testing.T) {
+	a, b := NewGatherOp(0, 1), NewGatherOp(0, 2)
+	if a.Hashcode() == b.Hashcode() {
+		t.Errorf("expected %v and %v to have different hashes", a, b)
+	}
+	if a.String() == b.String() {
+		t.Errorf("expected %v and %v to have different names", a, b)
+	}
+}
+
+func TestArgsortNode(t *testing.T) {
+	g := G.NewGraph()
+	x := G.NewMatrix(
+		g,
+		tensor.Float32,
+		G.WithShape(2, 3),
+		G.WithName("x"),
+		G.WithValue(tensor.NewDense(
+			tensor.Float32,
+			[]int{2, 3},
+			tensor.WithBacking([]float32{3, 1, 2, 0, 5, 4}),
+		)),
+	)
+
+	sorted, err := ArgsortNode(x, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sorted.Dtype() != tensor.Int {
+		t.Errorf("expected output of type %v but got %v", tensor.Int,
+			sorted.Dtype())
+	}
+
+	vm := G.NewTapeMachine(g)
+	defer vm.Close()
+	if err := vm.RunAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 3},
+		tensor.WithBacking([]int{1, 2, 0, 0, 2, 1}),
+	)
+	if !sorted.Value().(tensor.Tensor).Eq(out) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", out, sorted.Value())
+	}
+}
@@ -0,0 +1,127 @@
+// Package graph provides gorgonia.org/gorgonia operations wrapping the
+// tensor operations in package top, so that they can be used inside an
+// *gorgonia.ExprGraph.
+//
+// Each operation implements gorgonia's Op interface. Differentiable
+// operations also implement both the ADOp interface, for use with
+// gorgonia's LispMachine, and the SDOp interface, for use with
+// gorgonia.Grad and the TapeMachine. Non-differentiable operations
+// implement SDOp but report that they cannot be differentiated with
+// respect to any input.
+//
+// Each operation has a helper function which applies the operation to
+// nodes of a graph, for example:
+//
+//	g := gorgonia.NewGraph()
+//	x := gorgonia.NewMatrix(g, tensor.Float64, gorgonia.WithShape(2, 3))
+//	idx := gorgonia.NewMatrix(g, tensor.Int, gorgonia.WithShape(2, 1))
+//	gathered, err := graph.GatherNode(x, 1, idx)
+package graph
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+
+	"github.com/chewxy/hm"
+	G "gorgonia.org/gorgonia"
+	"gorgonia.org/tensor"
+)
+
+// tensorType returns the type of a tensor with dims dimensions holding
+// elements of type of. If dims is 0, the type is a scalar of type of.
+func tensorType(dims int, of hm.Type) hm.Type {
+	if dims == 0 {
+		return of
+	}
+	return G.TensorType{Dims: dims, Of: of}
+}
+
+// hashcode returns the hash of an Op using the Op's WriteHash method
+func hashcode(op G.Op) uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+// writeHash writes the string representation of an Op to h
+func writeHash(h hash.Hash, op fmt.Stringer) {
+	fmt.Fprint(h, op.String())
+}
+
+// checkArity returns an error if the number of inputs to an Op does not
+// match the Op's arity
+func checkArity(op G.Op, inputs int) error {
+	if inputs != op.Arity() {
+		return fmt.Errorf("%v: expected %v inputs but got %v", op,
+			op.Arity(), inputs)
+	}
+	return nil
+}
+
+// toTensor converts a gorgonia.Value to a tensor.Tensor
+func toTensor(v G.Value) (tensor.Tensor, error) {
+	t, ok := v.(tensor.Tensor)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type tensor.Tensor "+
+			"but got %T", v)
+	}
+	return t, nil
+}
+
+// toTensors converts each gorgonia.Value to a tensor.Tensor
+func toTensors(values ...G.Value) ([]tensor.Tensor, error) {
+	out := make([]tensor.Tensor, len(values))
+	for i := range values {
+		t, err := toTensor(values[i])
+		if err != nil {
+			return nil, fmt.Errorf("input %v: %v", i, err)
+		}
+		out[i] = t
+	}
+	return out, nil
+}
+
+// accumulateGrad adds grad to the gradient of n in place. This is
+// used in the DoDiff methods of ADOps.
+func accumulateGrad(n *G.Node, grad tensor.Tensor) error {
+	nGrad, err := n.Grad()
+	if err != nil {
+		return fmt.Errorf("could not get gradient of node %v: %v", n, err)
+	}
+
+	t, err := toTensor(nGrad)
+	if err != nil {
+		return fmt.Errorf("gradient of node %v: %v", n, err)
+	}
+
+	if _, err := tensor.Add(t, grad, tensor.UseUnsafe()); err != nil {
+		return fmt.Errorf("could not accumulate gradient of node %v: %v",
+			n, err)
+	}
+	return nil
+}
+
+// shapeOf returns the argument DimSizer as a tensor.Shape
+func shapeOf(ds G.DimSizer) (tensor.Shape, error) {
+	s, ok := ds.(tensor.Shape)
+	if !ok {
+		return nil, fmt.Errorf("expected tensor.Shape but got %T", ds)
+	}
+	return s, nil
+}
+
+// sameShape infers the output shape of a unary Op whose output has the
+// same shape as its input
+func sameShape(op G.Op, inputs ...G.DimSizer) (tensor.Shape, error) {
+	if len(inputs) != op.Arity() {
+		return nil, fmt.Errorf("%v: expected %v inputs but got %v", op,
+			op.Arity(), len(inputs))
+	}
+
+	s, err := shapeOf(inputs[0])
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", op, err)
+	}
+	return s.Clone(), nil
+}
@@ -0,0 +1,107 @@
+package top
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gorgonia.org/tensor"
+)
+
+// GumbelSoftmax draws a relaxed sample from the categorical
+// distribution along axis of the unnormalized log-probabilities logits,
+// using src as the source of randomness. Gumbel noise g is added to the
+// logits and the soft sample is computed as:
+//
+//	soft = Softmax((logits + g) / tau, axis)
+//
+// where the temperature tau must be positive. As tau approaches 0, the
+// soft samples approach one-hot samples from the distribution.
+//
+// If hard is false, the returned sample is the soft sample. If hard is
+// true, the returned sample is one-hot along axis at the maximum of the
+// soft sample, but GumbelSoftmaxB still computes the gradient of the
+// soft sample. This is the straight-through Gumbel-Softmax estimator.
+//
+// GumbelSoftmax returns the sample together with the soft sample, which
+// is needed by GumbelSoftmaxB. Both tensors have the same shape and
+// type as logits. GumbelSoftmax works on tensors of type float64 or
+// float32.
+func GumbelSoftmax(logits tensor.Tensor, tau float64, hard bool, axis int,
+	src rand.Source) (sample, soft tensor.Tensor, err error) {
+	if tau <= 0 {
+		return nil, nil, fmt.Errorf("gumbelSoftmax: tau must be positive "+
+			"but got %v", tau)
+	}
+	if err := checkSoftmaxArgs(logits, axis); err != nil {
+		return nil, nil, fmt.Errorf("gumbelSoftmax: %v", err)
+	}
+
+	rng := rand.New(src)
+	data, _ := toFloat64Data(logits)
+	perturbed := make([]float64, len(data))
+	for i := range data {
+		// Gumbel noise -log(-log(u)) with u in (0, 1)
+		u := rng.Float64()
+		for u == 0 {
+			u = rng.Float64()
+		}
+		perturbed[i] = (data[i] - math.Log(-math.Log(u))) / tau
+	}
+
+	soft, err = Softmax(fromFloat64Data(logits.Dtype(),
+		logits.Shape().Clone(), perturbed), axis)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gumbelSoftmax: %v", err)
+	}
+	if !hard {
+		return soft, soft, nil
+	}
+
+	// Set the maximum of each row to 1, breaking ties by choosing the
+	// first maximum
+	shape := soft.Shape()
+	s, _ := toFloat64Data(soft)
+	starts, stride := axisRows(shape, axis)
+	oneHot := make([]float64, len(s))
+	for _, start := range starts {
+		argmax := start
+		for j := 1; j < shape[axis]; j++ {
+			if i := start + j*stride; s[i] > s[argmax] {
+				argmax = i
+			}
+		}
+		if shape[axis] > 0 {
+			oneHot[argmax] = 1
+		}
+	}
+	return fromFloat64Data(soft.Dtype(), shape.Clone(), oneHot), soft, nil
+}
+
+// GumbelSoftmaxB is the backward pass of GumbelSoftmax. Given the
+// gradient grad with respect to the sample returned by GumbelSoftmax,
+// and the soft sample returned with it, GumbelSoftmaxB returns the
+// gradient with respect to the logits:
+//
+//	dlogits = SoftmaxB(grad, soft, axis) / tau
+//
+// The same gradient is returned whether or not hard samples were
+// drawn, so that hard samples use the straight-through estimator.
+func GumbelSoftmaxB(grad, soft tensor.Tensor, tau float64,
+	axis int) (tensor.Tensor, error) {
+	if tau <= 0 {
+		return nil, fmt.Errorf("gumbelSoftmaxB: tau must be positive but "+
+			"got %v", tau)
+	}
+
+	dx, err := SoftmaxB(grad, soft, axis)
+	if err != nil {
+		return nil, fmt.Errorf("gumbelSoftmaxB: %v", err)
+	}
+	data, _ := toFloat64Data(dx)
+	out := make([]float64, len(data))
+	for i := range data {
+		out[i] = data[i] / tau
+	}
+	return fromFloat64Data(dx.Dtype(), dx.Shape().Clone(), out), nil
+}
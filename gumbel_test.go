@@ -0,0 +1,124 @@
+package top
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestGumbelSoftmax(t *testing.T) {
+	const samples = 20000
+
+	logits := tensor.NewDense(
+		tensor.Float32,
+		[]int{3, 1},
+		tensor.WithBacking([]float32{
+			float32(math.Log(0.2)), float32(math.Log(0.5)),
+			float32(math.Log(0.3)),
+		}),
+	)
+	src := rand.NewSource(1)
+
+	counts := make([]float64, 3)
+	for i := 0; i < samples; i++ {
+		sample, soft, err := GumbelSoftmax(logits, 0.5, true, 0, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sample.Dtype() != tensor.Float32 || soft.Dtype() != tensor.Float32 {
+			t.Fatalf("expected samples of type %v", tensor.Float32)
+		}
+
+		s := sample.Data().([]float32)
+		p := soft.Data().([]float32)
+		total := float32(0)
+		argmax := 0
+		for j := range p {
+			total += p[j]
+			if p[j] > p[argmax] {
+				argmax = j
+			}
+		}
+		if math.Abs(float64(total)-1) > 1e-5 {
+			t.Fatalf("expected soft sample to sum to 1 but got %v", total)
+		}
+		for j := range s {
+			if (j == argmax && s[j] != 1) || (j != argmax && s[j] != 0) {
+				t.Fatalf("expected one-hot sample at %v but got %v", argmax,
+					s)
+			}
+		}
+		counts[argmax]++
+	}
+
+	// The argmax of the perturbed logits is distributed according to
+	// the softmax of the logits
+	for i, p := range []float64{0.2, 0.5, 0.3} {
+		if freq := counts[i] / samples; math.Abs(freq-p) > 0.015 {
+			t.Errorf("expected category %v with frequency %v but got %v",
+				i, p, freq)
+		}
+	}
+
+	if _, _, err := GumbelSoftmax(logits, 0, false, 0, src); err == nil {
+		t.Error("expected error for non-positive temperature")
+	}
+	if _, _, err := GumbelSoftmax(logits, 1, false, 2, src); err == nil {
+		t.Error("expected error for axis out of range")
+	}
+}
+
+func TestGumbelSoftmaxB(t *testing.T) {
+	const (
+		tau  = 0.7
+		seed = 1
+	)
+	logits := randTensor([]int{2, 4}, -1, 1)
+
+	// Each call uses the same noise, so that the forward pass is a
+	// deterministic function of the logits
+	gumbelSoftmax := func(logits tensor.Tensor, hard bool) (tensor.Tensor,
+		tensor.Tensor, error) {
+		return GumbelSoftmax(logits, tau, hard, 1, rand.NewSource(seed))
+	}
+
+	checkGrad(t, "gumbelSoftmax",
+		func(inputs []tensor.Tensor) (tensor.Tensor, error) {
+			sample, _, err := gumbelSoftmax(inputs[0], false)
+			return sample, err
+		},
+		func(grad tensor.Tensor, inputs []tensor.Tensor) ([]tensor.Tensor,
+			error) {
+			_, soft, err := gumbelSoftmax(inputs[0], false)
+			if err != nil {
+				return nil, err
+			}
+			dx, err := GumbelSoftmaxB(grad, soft, tau, 1)
+			return []tensor.Tensor{dx}, err
+		},
+		logits,
+	)
+
+	// Hard samples share the soft samples of the same noise, and so have
+	// the same straight-through gradient
+	sample, hardSoft, err := gumbelSoftmax(logits, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, soft, err := gumbelSoftmax(logits, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hardSoft.Eq(soft) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", soft, hardSoft)
+	}
+	if sample.Eq(soft) {
+		t.Error("expected hard sample to differ from soft sample")
+	}
+
+	if _, err := GumbelSoftmaxB(soft, soft, 0, 1); err == nil {
+		t.Error("expected error for non-positive temperature")
+	}
+}
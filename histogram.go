@@ -0,0 +1,327 @@
+package top
+
+import (
+	"fmt"
+	"math"
+
+	"gorgonia.org/tensor"
+)
+
+// Bincount counts the number of occurrences of each value in the
+// tensor t of non-negative integers, which is treated as if it were
+// flattened. The returned 1D tensor has length equal to the maximum
+// of minlength and one more than the largest value in t, and element
+// i of the returned tensor holds the number of occurrences of i in t.
+//
+// If weights is not nil, it must have the same shape as t and the
+// returned tensor holds the sum of the weights at each occurrence of
+// i in t rather than the count. In this case the returned tensor has
+// the same type as weights, which must be float64, float32, or any
+// int type. Otherwise, the returned tensor has type tensor.Int.
+//
+// The tensor t may have any integer type (e.g. uint8, int64, ...),
+// which will be converted to int before counting.
+//
+// See NumPy's documentation for more details and usage:
+// https://numpy.org/doc/stable/reference/generated/numpy.bincount.html
+func Bincount(t, weights tensor.Tensor, minlength int) (tensor.Tensor,
+	error) {
+	size := t.Shape().TotalSize()
+	flat, err := reshape(t, tensor.Shape{size})
+	if err != nil {
+		return nil, fmt.Errorf("bincount: %v", err)
+	}
+
+	var flatWeights tensor.Tensor
+	if weights != nil {
+		if !weights.Shape().Eq(t.Shape()) {
+			return nil, fmt.Errorf("bincount: weights must have shape %v "+
+				"but got %v", t.Shape(), weights.Shape())
+		}
+		flatWeights, err = reshape(weights, tensor.Shape{size})
+		if err != nil {
+			return nil, fmt.Errorf("bincount: %v", err)
+		}
+	}
+
+	out, err := BincountAlong(flat, 0, flatWeights, minlength)
+	if err != nil {
+		return nil, fmt.Errorf("bincount: %v", err)
+	}
+	return out, nil
+}
+
+// BincountAlong is the batched version of Bincount, which counts the
+// occurrences of each value in each row of t along axis independently.
+// The returned tensor has the same shape as t, except that axis has
+// length equal to the maximum of minlength and one more than the
+// largest value in t. See Bincount for more details.
+func BincountAlong(t tensor.Tensor, axis int, weights tensor.Tensor,
+	minlength int) (tensor.Tensor, error) {
+	shape := t.Shape()
+	if axis < 0 || axis >= len(shape) {
+		return nil, fmt.Errorf("bincountAlong: axis out of range [%v] for "+
+			"tensor with %v dimensions", axis, len(shape))
+	}
+	if minlength < 0 {
+		return nil, fmt.Errorf("bincountAlong: minlength must be "+
+			"non-negative but got %v", minlength)
+	}
+
+	data, err := intData(t)
+	if err != nil {
+		return nil, fmt.Errorf("bincountAlong: %v", err)
+	}
+
+	length := minlength
+	for _, value := range data {
+		if value < 0 {
+			return nil, fmt.Errorf("bincountAlong: values must be "+
+				"non-negative but got %v", value)
+		}
+		if value+1 > length {
+			length = value + 1
+		}
+	}
+
+	outShape := shape.Clone()
+	outShape[axis] = length
+	starts, stride := axisRows(shape, axis)
+	outStarts, outStride := axisRows(outShape, axis)
+
+	// bins returns the index into the output backing slice of the bin
+	// in which element i of t along row should be counted
+	bins := func(row, i int) int {
+		value := data[starts[row]+i*stride]
+		return outStarts[row] + value*outStride
+	}
+
+	if weights == nil {
+		out := make([]int, outShape.TotalSize())
+		for row := range starts {
+			for i := 0; i < shape[axis]; i++ {
+				out[bins(row, i)]++
+			}
+		}
+		return newTensor(outShape, out), nil
+	}
+
+	if !weights.Shape().Eq(shape) {
+		return nil, fmt.Errorf("bincountAlong: weights must have shape %v "+
+			"but got %v", shape, weights.Shape())
+	}
+
+	switch {
+	case weights.Dtype() == tensor.Float64:
+		w, _ := float64Data(weights)
+		out := make([]float64, outShape.TotalSize())
+		for row := range starts {
+			for i := 0; i < shape[axis]; i++ {
+				out[bins(row, i)] += w[starts[row]+i*stride]
+			}
+		}
+		return newTensor(outShape, out), nil
+
+	case weights.Dtype() == tensor.Float32:
+		w, _ := float32Data(weights)
+		out := make([]float32, outShape.TotalSize())
+		for row := range starts {
+			for i := 0; i < shape[axis]; i++ {
+				out[bins(row, i)] += w[starts[row]+i*stride]
+			}
+		}
+		return newTensor(outShape, out), nil
+
+	case isIntDtype(weights.Dtype()):
+		w, _ := intData(weights)
+		out := make([]int, outShape.TotalSize())
+		for row := range starts {
+			for i := 0; i < shape[axis]; i++ {
+				out[bins(row, i)] += w[starts[row]+i*stride]
+			}
+		}
+		return newTensor(outShape, out), nil
+
+	default:
+		return nil, fmt.Errorf("bincountAlong: unknown weights type %v",
+			weights.Dtype())
+	}
+}
+
+// Histogram computes the histogram of the elements of t, which is
+// treated as if it were flattened. The histogram has bins equal-width
+// bins in the range given by bounds, which holds the lower and upper
+// edges of the first and last bins. If bounds is nil, the range from
+// the smallest to the largest element of t is used instead. If both
+// edges are equal, the range is widened by 0.5 on each side. Elements
+// outside of the range and NaN elements are ignored. Each bin includes
+// its left edge, and the last bin also includes its right edge.
+//
+// Two float64 tensors are returned: the 1D histogram with bins
+// elements and the 1D tensor of the bins+1 bin edges. If weights is
+// not nil, it must have the same shape as t and each element of t
+// contributes its weight rather than 1 to its bin. If density is true,
+// the histogram is normalized such that it integrates to 1 over the
+// range.
+//
+// Histogram works on tensors t of type float64, float32, or any int
+// type, and weights of type float64 or float32.
+//
+// See NumPy's documentation for more details and usage:
+// https://numpy.org/doc/stable/reference/generated/numpy.histogram.html
+func Histogram(t tensor.Tensor, bins int, bounds *[2]float64,
+	weights tensor.Tensor, density bool) (tensor.Tensor, tensor.Tensor,
+	error) {
+	size := t.Shape().TotalSize()
+	data, err := numericFloat64Data(t)
+	if err != nil {
+		return nil, nil, fmt.Errorf("histogram: %v", err)
+	}
+	flat := newTensor(tensor.Shape{size}, data)
+
+	var flatWeights tensor.Tensor
+	if weights != nil {
+		if !weights.Shape().Eq(t.Shape()) {
+			return nil, nil, fmt.Errorf("histogram: weights must have shape "+
+				"%v but got %v", t.Shape(), weights.Shape())
+		}
+		w, err := toFloat64Data(weights)
+		if err != nil {
+			return nil, nil, fmt.Errorf("histogram: weights: %v", err)
+		}
+		flatWeights = newTensor(tensor.Shape{size}, w)
+	}
+
+	hist, edges, err := HistogramAlong(flat, 0, bins, bounds,
+		flatWeights, density)
+	if err != nil {
+		return nil, nil, fmt.Errorf("histogram: %v", err)
+	}
+	return hist, edges, nil
+}
+
+// HistogramAlong is the batched version of Histogram, which computes
+// the histogram of each row of t along axis independently. All rows
+// share the same bin edges. If bounds is nil, the range from the
+// smallest to the largest element over all of t is used. The returned
+// histogram has the same shape as t, except that axis has length bins.
+// See Histogram for more details.
+func HistogramAlong(t tensor.Tensor, axis, bins int,
+	bounds *[2]float64, weights tensor.Tensor, density bool) (tensor.Tensor,
+	tensor.Tensor, error) {
+	shape := t.Shape()
+	if axis < 0 || axis >= len(shape) {
+		return nil, nil, fmt.Errorf("histogramAlong: axis out of range [%v] "+
+			"for tensor with %v dimensions", axis, len(shape))
+	}
+	if bins < 1 {
+		return nil, nil, fmt.Errorf("histogramAlong: bins must be positive "+
+			"but got %v", bins)
+	}
+	if bounds != nil {
+		if math.IsNaN(bounds[0]) || math.IsInf(bounds[0], 0) ||
+			math.IsNaN(bounds[1]) || math.IsInf(bounds[1], 0) {
+			return nil, nil, fmt.Errorf("histogramAlong: range must be "+
+				"finite but got %v", *bounds)
+		}
+		if bounds[0] > bounds[1] {
+			return nil, nil, fmt.Errorf("histogramAlong: lower edge (%v) "+
+				"must not be larger than upper edge (%v)", bounds[0],
+				bounds[1])
+		}
+	}
+
+	data, err := numericFloat64Data(t)
+	if err != nil {
+		return nil, nil, fmt.Errorf("histogramAlong: %v", err)
+	}
+
+	var w []float64
+	if weights != nil {
+		if !weights.Shape().Eq(shape) {
+			return nil, nil, fmt.Errorf("histogramAlong: weights must have "+
+				"shape %v but got %v", shape, weights.Shape())
+		}
+		w, err = toFloat64Data(weights)
+		if err != nil {
+			return nil, nil, fmt.Errorf("histogramAlong: weights: %v", err)
+		}
+	}
+
+	// Compute the range from the data if needed
+	var lo, hi float64
+	if bounds != nil {
+		lo, hi = bounds[0], bounds[1]
+	} else {
+		lo, hi = math.Inf(1), math.Inf(-1)
+		for _, value := range data {
+			if math.IsNaN(value) {
+				continue
+			}
+			lo = math.Min(lo, value)
+			hi = math.Max(hi, value)
+		}
+		if math.IsInf(lo, 1) {
+			lo, hi = 0, 1
+		}
+	}
+	if lo == hi {
+		lo, hi = lo-0.5, hi+0.5
+	}
+
+	edges := make([]float64, bins+1)
+	width := (hi - lo) / float64(bins)
+	for i := range edges {
+		edges[i] = lo + float64(i)*width
+	}
+	edges[bins] = hi
+
+	outShape := shape.Clone()
+	outShape[axis] = bins
+	starts, stride := axisRows(shape, axis)
+	outStarts, outStride := axisRows(outShape, axis)
+
+	hist := make([]float64, outShape.TotalSize())
+	for row := range starts {
+		total := 0.0
+		for i := 0; i < shape[axis]; i++ {
+			j := starts[row] + i*stride
+			value := data[j]
+			if value < lo || value > hi || math.IsNaN(value) {
+				continue
+			}
+
+			bin := int((value - lo) / width)
+			if bin >= bins {
+				// The last bin includes its right edge
+				bin = bins - 1
+			}
+
+			// Rounding can place values lying on or near an edge in
+			// the wrong bin, so the bin is corrected against the
+			// returned edges
+			for bin > 0 && value < edges[bin] {
+				bin--
+			}
+			for bin < bins-1 && value >= edges[bin+1] {
+				bin++
+			}
+
+			weight := 1.0
+			if w != nil {
+				weight = w[j]
+			}
+			hist[outStarts[row]+bin*outStride] += weight
+			total += weight
+		}
+
+		if density && total != 0 {
+			for bin := 0; bin < bins; bin++ {
+				hist[outStarts[row]+bin*outStride] /= total * width
+			}
+		}
+	}
+
+	return newTensor(outShape, hist), newTensor(tensor.Shape{bins + 1},
+		edges), nil
+}
@@ -0,0 +1,186 @@
+package top
+
+import (
+	"math"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestBincount(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Uint8,
+		[]int{2, 3},
+		tensor.WithBacking([]uint8{0, 1, 1, 3, 1, 0}),
+	)
+
+	// Counts
+	counts := tensor.NewDense(
+		tensor.Int,
+		[]int{6},
+		tensor.WithBacking([]int{2, 3, 0, 1, 0, 0}),
+	)
+	out, err := Bincount(in, nil, 6)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(counts) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", counts, out)
+	}
+
+	// Weighted counts
+	weights := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{0.5, 1, 2, 4, 8, 16}),
+	)
+	weighted := tensor.NewDense(
+		tensor.Float64,
+		[]int{4},
+		tensor.WithBacking([]float64{16.5, 11, 0, 4}),
+	)
+	out, err = Bincount(in, weights, 0)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(weighted) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", weighted, out)
+	}
+
+	// Counts along each row
+	rows := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 4},
+		tensor.WithBacking([]int{1, 2, 0, 0, 1, 1, 0, 1}),
+	)
+	out, err = BincountAlong(in, 1, nil, 0)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(rows) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", rows, out)
+	}
+
+	// Negative values cannot be counted
+	negative := tensor.NewDense(
+		tensor.Int,
+		[]int{2},
+		tensor.WithBacking([]int{1, -1}),
+	)
+	if _, err := Bincount(negative, nil, 0); err == nil {
+		t.Error("expected error for negative values")
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 4},
+		tensor.WithBacking([]float32{0, 0.1, 0.6, 1, 0.5, 0.4, 2, -1}),
+	)
+
+	hist := tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{3, 3}),
+	)
+	edges := tensor.NewDense(
+		tensor.Float64,
+		[]int{3},
+		tensor.WithBacking([]float64{0, 0.5, 1}),
+	)
+
+	out, outEdges, err := Histogram(in, 2, &[2]float64{0, 1}, nil, false)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(hist) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", hist, out)
+	}
+	if !outEdges.Eq(edges) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", edges, outEdges)
+	}
+
+	// Batched densities with the range computed from the data
+	density := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{0, 0.75, 0.25, 0.25, 0.5, 0.25}),
+	)
+	edges = tensor.NewDense(
+		tensor.Float64,
+		[]int{4},
+		tensor.WithBacking([]float64{-1, 0, 1, 2}),
+	)
+
+	out, outEdges, err = HistogramAlong(in, 1, 3, nil, nil, true)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(density) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", density, out)
+	}
+	if !outEdges.Eq(edges) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", edges, outEdges)
+	}
+
+	// Values lying exactly on interior edges are counted in the bin to
+	// the right of the edge, even when the edges are not exactly
+	// representable
+	_, outEdges, err = Histogram(in, 5, &[2]float64{0.1, 0.7}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interior := outEdges.Data().([]float64)[1:5]
+	onEdges := tensor.NewDense(
+		tensor.Float64,
+		[]int{len(interior)},
+		tensor.WithBacking(append([]float64(nil), interior...)),
+	)
+	hist = tensor.NewDense(
+		tensor.Float64,
+		[]int{5},
+		tensor.WithBacking([]float64{0, 1, 1, 1, 1}),
+	)
+
+	out, _, err = Histogram(onEdges, 5, &[2]float64{0.1, 0.7}, nil,
+		false)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(hist) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", hist, out)
+	}
+
+	// A degenerate range is widened by 0.5 on each side
+	hist = tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{2, 1}),
+	)
+	edges = tensor.NewDense(
+		tensor.Float64,
+		[]int{3},
+		tensor.WithBacking([]float64{0.5, 1, 1.5}),
+	)
+	out, outEdges, err = Histogram(in, 2, &[2]float64{1, 1}, nil, false)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(hist) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", hist, out)
+	}
+	if !outEdges.Eq(edges) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", edges, outEdges)
+	}
+
+	// Illegal ranges
+	if _, _, err := Histogram(in, 2, &[2]float64{1, 0}, nil,
+		false); err == nil {
+		t.Error("expected error for decreasing range")
+	}
+	if _, _, err := Histogram(in, 2, &[2]float64{0, math.Inf(1)}, nil,
+		false); err == nil {
+		t.Error("expected error for infinite range")
+	}
+}
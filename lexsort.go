@@ -0,0 +1,85 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// Lexsort returns an int tensor containing the indices that would
+// sort the keys lexicographically along axis. The last key is the
+// primary sort key, the second last key is the secondary sort key,
+// and so on. That is, elements are ordered by the last key, and ties
+// are broken by the earlier keys. The sort is stable, so elements
+// which are equal in every key remain in their original order.
+//
+// All keys must have the same shape, and each key may have type
+// float64, float32, or any int type, independently of the types of the
+// other keys. NaN values are sorted after all other values.
+//
+// See NumPy's documentation for more details and usage:
+// https://numpy.org/doc/stable/reference/generated/numpy.lexsort.html
+func Lexsort(keys []tensor.Tensor, axis int) (tensor.Tensor, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("lexsort: at least one key is required")
+	}
+
+	shape := keys[0].Shape()
+	if axis < 0 || axis >= len(shape) {
+		return nil, fmt.Errorf("lexsort: axis out of range [%v] for "+
+			"tensor with %v dimensions", axis, len(shape))
+	}
+
+	cmps := make([]func(i, j int) int, len(keys))
+	for k, key := range keys {
+		if !key.Shape().Eq(shape) {
+			return nil, fmt.Errorf("lexsort: all keys must have the same "+
+				"shape but got %v and %v", shape, key.Shape())
+		}
+
+		cmp, err := comparer(key, true)
+		if err != nil {
+			return nil, fmt.Errorf("lexsort: key %v: %v", k, err)
+		}
+		cmps[k] = cmp
+	}
+
+	starts, stride := axisRows(shape, axis)
+	sorted := make([]int, shape.TotalSize())
+	for _, start := range starts {
+		s := &lexSorter{cmps: cmps, indices: make([]int, shape[axis])}
+		for i := range s.indices {
+			s.indices[i] = start + i*stride
+		}
+
+		for i, arg := range argSort(s) {
+			sorted[start+i*stride] = arg
+		}
+	}
+
+	return newTensor(shape.Clone(), sorted), nil
+}
+
+// lexSorter implements sort.Interface to lexicographically compare the
+// elements of a row of multiple keys. Since lexSorter is only sorted
+// indirectly through argSort, Swap does nothing.
+type lexSorter struct {
+	cmps    []func(i, j int) int
+	indices []int // Row-major indices of the row's elements in each key
+}
+
+// Len implements the interface sort.Interface
+func (l *lexSorter) Len() int { return len(l.indices) }
+
+// Less implements the interface sort.Interface
+func (l *lexSorter) Less(i, j int) bool {
+	for k := len(l.cmps) - 1; k >= 0; k-- {
+		if c := l.cmps[k](l.indices[i], l.indices[j]); c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+// Swap implements the interface sort.Interface
+func (l *lexSorter) Swap(i, j int) {}
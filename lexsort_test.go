@@ -0,0 +1,65 @@
+package top
+
+import (
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestLexsort(t *testing.T) {
+	// Transitions ordered by (episode, timestep)
+	timestep := tensor.NewDense(
+		tensor.Float32,
+		[]int{6},
+		tensor.WithBacking([]float32{2, 0, 1, 1, 0, 0}),
+	)
+	episode := tensor.NewDense(
+		tensor.Int64,
+		[]int{6},
+		tensor.WithBacking([]int64{1, 1, 0, 1, 0, 1}),
+	)
+	target := tensor.NewDense(
+		tensor.Int,
+		[]int{6},
+		tensor.WithBacking([]int{4, 2, 1, 5, 3, 0}),
+	)
+
+	out, err := Lexsort([]tensor.Tensor{timestep, episode}, 0)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+}
+
+// TestLexsortArgsort tests if Lexsort with a single key is equivalent
+// to Argsort
+func TestLexsortArgsort(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float64,
+		[]int{3, 3},
+		tensor.WithBacking([]float64{1, 5, 0, 3, 9, 8, 4, 6, 7}),
+	)
+
+	for axis := 0; axis < 2; axis++ {
+		target, err := Argsort(in, axis)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := Lexsort([]tensor.Tensor{in}, axis)
+		if err != nil {
+			t.Error(err)
+		}
+		if !out.Eq(target) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+		}
+	}
+
+	// Keys of different shapes are errors
+	bad := tensor.NewDense(tensor.Float64, []int{9})
+	if _, err := Lexsort([]tensor.Tensor{in, bad}, 0); err == nil {
+		t.Error("expected error for keys of different shapes")
+	}
+}
@@ -0,0 +1,113 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// MaxAlong returns the maximum values of t along axis, together with an
+// int tensor holding the indices of these maximum values along axis.
+// If keepdims is true, the returned tensors have the same number of
+// dimensions as t with axis having size 1. Otherwise, axis is removed
+// from the shapes of the returned tensors.
+//
+// When multiple elements along axis tie for the maximum, the index of
+// the first is returned. NaN values are considered larger than any
+// other value.
+//
+// MaxAlong works on tensors of type float64, float32, or any int type.
+// If t has an integer type (e.g. uint32), the returned values will
+// be converted to int and stored in a tensor of type tensor.Int.
+func MaxAlong(t tensor.Tensor, axis int, keepdims bool) (tensor.Tensor,
+	tensor.Tensor, error) {
+	values, indices, err := extremumAlong(t, axis, keepdims, 1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("maxAlong: %v", err)
+	}
+	return values, indices, nil
+}
+
+// MinAlong returns the minimum values of t along axis, together with an
+// int tensor holding the indices of these minimum values along axis.
+// NaN values are considered smaller than any other value. See MaxAlong
+// for more details.
+func MinAlong(t tensor.Tensor, axis int, keepdims bool) (tensor.Tensor,
+	tensor.Tensor, error) {
+	values, indices, err := extremumAlong(t, axis, keepdims, -1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("minAlong: %v", err)
+	}
+	return values, indices, nil
+}
+
+// extremumAlong returns the extreme values of t along axis, together
+// with their indices. If sign is positive, the maximum values are
+// found, otherwise the minimum values are found.
+func extremumAlong(t tensor.Tensor, axis int, keepdims bool,
+	sign int) (tensor.Tensor, tensor.Tensor, error) {
+	indices, err := argExtremum(t, axis, true, TieFirst, sign)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := Gather(t, axis, indices)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !keepdims {
+		shape := reducedShape(t.Shape(), axis, false)
+		values = newTensor(shape, values.Data())
+		indices = newTensor(shape, indices.Data())
+	}
+	return values, indices, nil
+}
+
+// MaxAlongB is the backward pass of MaxAlong and MinAlong. Given the
+// gradient grad with respect to the values returned by MaxAlong, and
+// the indices returned by MaxAlong, MaxAlongB returns the gradient with
+// respect to the input tensor of MaxAlong, which had shape inputShape.
+// The gradient is routed only to the maximal elements, and is zero
+// everywhere else.
+//
+// The grad and indices tensors must have the same shape, which may be
+// the shape resulting from calling MaxAlong with keepdims either true
+// or false. The grad tensor must store float64's, float32's, or any
+// integer type. If grad has an integer type, the returned tensor will
+// be of type tensor.Int.
+func MaxAlongB(grad, indices tensor.Tensor, inputShape tensor.Shape,
+	axis int) (tensor.Tensor, error) {
+	if axis < 0 || axis >= len(inputShape) {
+		return nil, fmt.Errorf("maxAlongB: axis out of range [%v] for "+
+			"tensor with %v dimensions", axis, len(inputShape))
+	}
+
+	// Insert the reduced axis if keepdims was false in the forward pass
+	if len(indices.Shape()) == len(inputShape)-1 {
+		shape := reducedShape(inputShape, axis, true)
+		if !indices.Shape().Eq(reducedShape(inputShape, axis, false)) {
+			return nil, fmt.Errorf("maxAlongB: expected indices to have "+
+				"shape %v but got %v", shape, indices.Shape())
+		}
+		if !grad.Shape().Eq(indices.Shape()) {
+			return nil, fmt.Errorf("maxAlongB: grad and indices must have "+
+				"the same shape but got grad=%v and indices=%v",
+				grad.Shape(), indices.Shape())
+		}
+
+		var err error
+		if grad, err = reshape(grad, shape); err != nil {
+			return nil, fmt.Errorf("maxAlongB: %v", err)
+		}
+		if indices, err = reshape(indices, shape); err != nil {
+			return nil, fmt.Errorf("maxAlongB: %v", err)
+		}
+	}
+
+	out, err := ScatterAdd(grad, axis, indices, inputShape)
+	if err != nil {
+		return nil, fmt.Errorf("maxAlongB: %v", err)
+	}
+	return out, nil
+}
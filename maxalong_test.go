@@ -0,0 +1,107 @@
+package top
+
+import (
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestMaxAlong(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{1, 7, 3, 9, 2, 9}),
+	)
+
+	maxValues := tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{7, 9}),
+	)
+	maxIndices := tensor.NewDense(
+		tensor.Int,
+		[]int{2},
+		tensor.WithBacking([]int{1, 0}),
+	)
+
+	values, indices, err := MaxAlong(in, 1, false)
+	if err != nil {
+		t.Error(err)
+	}
+	if !values.Eq(maxValues) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", maxValues, values)
+	}
+	if !indices.Eq(maxIndices) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", maxIndices, indices)
+	}
+
+	minValues := tensor.NewDense(
+		tensor.Float64,
+		[]int{1, 3},
+		tensor.WithBacking([]float64{1, 2, 3}),
+	)
+	minIndices := tensor.NewDense(
+		tensor.Int,
+		[]int{1, 3},
+		tensor.WithBacking([]int{0, 1, 0}),
+	)
+
+	values, indices, err = MinAlong(in, 0, true)
+	if err != nil {
+		t.Error(err)
+	}
+	if !values.Eq(minValues) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", minValues, values)
+	}
+	if !indices.Eq(minIndices) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", minIndices, indices)
+	}
+}
+
+func TestMaxAlongB(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 3},
+		tensor.WithBacking([]float32{1, 7, 3, 9, 2, 9}),
+	)
+	grad := tensor.NewDense(
+		tensor.Float32,
+		[]int{2},
+		tensor.WithBacking([]float32{0.5, -2}),
+	)
+	target := tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 3},
+		tensor.WithBacking([]float32{0, 0.5, 0, -2, 0, 0}),
+	)
+
+	for _, keepdims := range []bool{true, false} {
+		_, indices, err := MaxAlong(in, 1, keepdims)
+		if err != nil {
+			t.Error(err)
+		}
+
+		g := grad
+		if keepdims {
+			g = tensor.NewDense(
+				tensor.Float32,
+				[]int{2, 1},
+				tensor.WithBacking([]float32{0.5, -2}),
+			)
+		}
+
+		out, err := MaxAlongB(g, indices, in.Shape(), 1)
+		if err != nil {
+			t.Error(err)
+		}
+		if !out.Eq(target) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+		}
+	}
+
+	// Gradient and indices of different shapes are errors
+	indices := tensor.NewDense(tensor.Int, []int{3})
+	if _, err := MaxAlongB(grad, indices, in.Shape(), 1); err == nil {
+		t.Error("expected error for illegal shapes")
+	}
+}
@@ -0,0 +1,139 @@
+package top
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gorgonia.org/tensor"
+)
+
+// Multinomial samples numSamples indices along the last axis of probs
+// from the categorical distribution defined by each row of probs,
+// using src as the source of randomness. The probabilities need not be
+// normalized, but must be non-negative and each row must have a
+// non-zero sum. Categories with zero probability are never sampled.
+//
+// The returned int tensor has the shape of probs with the size of its
+// last axis replaced by numSamples, so that it can be used directly as
+// the indices of Gather along the last axis.
+//
+// If replacement is true, samples are drawn independently by
+// inverting the cumulative distribution function of each row, found
+// with a binary search over the cumulative sums of the row. Otherwise,
+// samples are drawn without replacement using the Gumbel-top-k trick:
+// Gumbel noise is added to the log-probabilities and the indices of the
+// numSamples largest perturbed values are returned in descending order
+// of their perturbed values. In this case, numSamples must not exceed
+// the number of categories with non-zero probability in any row.
+//
+// Multinomial works on tensors of type float64 or float32.
+//
+// This implementation is based on the PyTorch implementation. See
+// PyTorch's documentation for more details and usage:
+// https://pytorch.org/docs/stable/generated/torch.multinomial.html
+func Multinomial(probs tensor.Tensor, numSamples int, replacement bool,
+	src rand.Source) (tensor.Tensor, error) {
+	shape := probs.Shape()
+	if len(shape) == 0 {
+		return nil, fmt.Errorf("multinomial: probs cannot be a scalar")
+	}
+	if numSamples < 0 {
+		return nil, fmt.Errorf("multinomial: numSamples must be "+
+			"non-negative but got %v", numSamples)
+	}
+	data, err := toFloat64Data(probs)
+	if err != nil {
+		return nil, fmt.Errorf("multinomial: %v", err)
+	}
+
+	k := shape[len(shape)-1]
+	if k == 0 {
+		return nil, fmt.Errorf("multinomial: expected at least 1 category")
+	}
+	rows := len(data) / k
+	outShape := append(shape[:len(shape)-1].Clone(), numSamples)
+
+	// Compute the cumulative sums of each row, checking that each row
+	// defines a valid distribution
+	cdf := make([]float64, len(data))
+	for r := 0; r < rows; r++ {
+		nonZero := 0
+		total := 0.0
+		for i := r * k; i < (r+1)*k; i++ {
+			if data[i] < 0 || math.IsNaN(data[i]) || math.IsInf(data[i], 1) {
+				return nil, fmt.Errorf("multinomial: expected finite, "+
+					"non-negative probabilities but got %v", data[i])
+			}
+			if data[i] > 0 {
+				nonZero++
+			}
+			total += data[i]
+			cdf[i] = total
+		}
+
+		if total == 0 {
+			return nil, fmt.Errorf("multinomial: probabilities of row %v "+
+				"sum to 0", r)
+		}
+		if !replacement && numSamples > nonZero {
+			return nil, fmt.Errorf("multinomial: cannot draw %v samples "+
+				"without replacement from row %v with %v categories of "+
+				"non-zero probability", numSamples, r, nonZero)
+		}
+	}
+
+	rng := rand.New(src)
+	if !replacement {
+		return gumbelTopK(data, k, numSamples, outShape, rng)
+	}
+
+	// Draw uniform values in [0, total) for each row, and find the first
+	// category whose cumulative sum exceeds each value. Categories with
+	// zero probability have the same cumulative sum as their
+	// predecessor, and so are never found.
+	values := make([]float64, rows*numSamples)
+	for j := range values {
+		r := j / numSamples
+		values[j] = rng.Float64() * cdf[(r+1)*k-1]
+	}
+	indices, err := SearchSorted(newTensor(shape.Clone(), cdf),
+		newTensor(outShape, values), SideRight, nil)
+	if err != nil {
+		return nil, fmt.Errorf("multinomial: %v", err)
+	}
+	return indices, nil
+}
+
+// gumbelTopK samples numSamples indices without replacement from each
+// row of k unnormalized probabilities in data using the Gumbel-top-k
+// trick, returning a tensor of the argument shape
+func gumbelTopK(data []float64, k, numSamples int, shape tensor.Shape,
+	rng *rand.Rand) (tensor.Tensor, error) {
+	// Negate the perturbed log-probabilities so that sorting in
+	// ascending order visits the largest perturbed values first.
+	// Categories with zero probability have keys of +Inf and so are
+	// sorted last.
+	keys := make([]float64, len(data))
+	for i, p := range data {
+		// Gumbel noise -log(-log(u)) with u in (0, 1)
+		u := rng.Float64()
+		for u == 0 {
+			u = rng.Float64()
+		}
+		keys[i] = -(math.Log(p) - math.Log(-math.Log(u)))
+	}
+
+	rows := len(data) / k
+	order, err := Argsort(newTensor(tensor.Shape{rows, k}, keys), 1)
+	if err != nil {
+		return nil, fmt.Errorf("multinomial: %v", err)
+	}
+	o, _ := intData(order)
+
+	out := make([]int, rows*numSamples)
+	for r := 0; r < rows; r++ {
+		copy(out[r*numSamples:(r+1)*numSamples], o[r*k:r*k+numSamples])
+	}
+	return newTensor(shape, out), nil
+}
@@ -0,0 +1,155 @@
+package top
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestMultinomial(t *testing.T) {
+	const samples = 20000
+
+	// Unnormalized probabilities with a zero-probability category
+	probs := tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 4},
+		tensor.WithBacking([]float32{1, 0, 3, 0, 0, 2, 2, 4}),
+	)
+	src := rand.NewSource(1)
+
+	out, err := Multinomial(probs, samples, true, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Dtype() != tensor.Int || !equalShapes(out.Shape(),
+		tensor.Shape{2, samples}) {
+		t.Fatalf("expected int tensor of shape (2, %v) but got %v tensor "+
+			"of shape %v", samples, out.Dtype(), out.Shape())
+	}
+
+	targets := [][]float64{{0.25, 0, 0.75, 0}, {0, 0.25, 0.25, 0.5}}
+	indices := out.Data().([]int)
+	for r, target := range targets {
+		counts := make([]float64, 4)
+		for _, i := range indices[r*samples : (r+1)*samples] {
+			counts[i]++
+		}
+		for i := range counts {
+			freq := counts[i] / samples
+			if target[i] == 0 && freq != 0 {
+				t.Errorf("row %v: sampled category %v with zero probability",
+					r, i)
+			} else if math.Abs(freq-target[i]) > 0.015 {
+				t.Errorf("row %v: expected category %v with frequency %v "+
+					"but got %v", r, i, target[i], freq)
+			}
+		}
+	}
+
+	// Samples can be used to gather along the last axis
+	gathered, err := Gather(probs, 1, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range gathered.Data().([]float32) {
+		if p == 0 {
+			t.Fatal("gathered zero probability")
+		}
+	}
+
+	// 1D probabilities
+	out, err = Multinomial(tensor.New(tensor.WithShape(3),
+		tensor.WithBacking([]float64{0, 0, 1})), 5, true, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := tensor.New(tensor.WithShape(5),
+		tensor.WithBacking([]int{2, 2, 2, 2, 2}))
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+}
+
+func TestMultinomialWithoutReplacement(t *testing.T) {
+	const trials = 20000
+
+	probs := tensor.NewDense(
+		tensor.Float64,
+		[]int{3},
+		tensor.WithBacking([]float64{0.2, 0.5, 0.3}),
+	)
+	src := rand.NewSource(1)
+
+	first := make([]float64, 3)
+	for i := 0; i < trials; i++ {
+		out, err := Multinomial(probs, 3, false, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		indices := out.Data().([]int)
+		seen := make([]bool, 3)
+		for _, j := range indices {
+			if seen[j] {
+				t.Fatalf("sampled category %v twice: %v", j, indices)
+			}
+			seen[j] = true
+		}
+		first[indices[0]]++
+	}
+
+	// The first sample follows the distribution given by probs
+	for i, p := range probs.Data().([]float64) {
+		if freq := first[i] / trials; math.Abs(freq-p) > 0.015 {
+			t.Errorf("expected category %v first with frequency %v but got "+
+				"%v", i, p, freq)
+		}
+	}
+
+	// Categories with zero probability are never sampled
+	probs = tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{0, 1, 1, 1, 0, 1}),
+	)
+	out, err := Multinomial(probs, 2, false, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, j := range out.Data().([]int) {
+		if (i < 2 && j == 0) || (i >= 2 && j == 1) {
+			t.Errorf("sampled category with zero probability: %v", out)
+		}
+	}
+	if _, err := Multinomial(probs, 3, false, src); err == nil {
+		t.Error("expected error for too many samples without replacement")
+	}
+}
+
+func TestMultinomialErrors(t *testing.T) {
+	src := rand.NewSource(1)
+	cases := []struct {
+		name  string
+		probs tensor.Tensor
+		n     int
+	}{
+		{"scalar", tensor.New(tensor.FromScalar(1.0)), 1},
+		{"negative", tensor.New(tensor.WithShape(2),
+			tensor.WithBacking([]float64{1, -1})), 1},
+		{"nan", tensor.New(tensor.WithShape(2),
+			tensor.WithBacking([]float64{1, math.NaN()})), 1},
+		{"zero sum", tensor.New(tensor.WithShape(2, 2),
+			tensor.WithBacking([]float64{1, 0, 0, 0})), 1},
+		{"int", tensor.New(tensor.WithShape(2),
+			tensor.WithBacking([]int{1, 1})), 1},
+		{"negative samples", tensor.New(tensor.WithShape(2),
+			tensor.WithBacking([]float64{1, 1})), -1},
+	}
+
+	for _, c := range cases {
+		if _, err := Multinomial(c.probs, c.n, true, src); err == nil {
+			t.Errorf("%v: expected error", c.name)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// Nonzero returns an int tensor of shape (n, t.Dims()) holding the
+// coordinates of the n nonzero elements of t, one coordinate per row.
+// Coordinates are listed in row-major order. For bool tensors, the
+// true elements are considered nonzero.
+//
+// Nonzero works on tensors t of type bool, float64, float32, or any
+// int type. If t has no nonzero elements, the returned tensor has
+// shape (0, t.Dims()) and has no backing data.
+//
+// This implementation follows PyTorch's nonzero function. See
+// PyTorch's documentation for more details and usage:
+// https://pytorch.org/docs/stable/generated/torch.nonzero.html
+func Nonzero(t tensor.Tensor) (tensor.Tensor, error) {
+	coords, err := nonzeroCoords(t)
+	if err != nil {
+		return nil, fmt.Errorf("nonzero: %v", err)
+	}
+
+	dims := len(t.Shape())
+	backing := make([]int, 0, len(coords)*dims)
+	for _, coord := range coords {
+		backing = append(backing, coord...)
+	}
+
+	return tensor.NewDense(
+		tensor.Int,
+		[]int{len(coords), dims},
+		tensor.WithBacking(backing),
+	), nil
+}
+
+// ArgWhere is an alias of Nonzero, named after NumPy's argwhere
+// function.
+func ArgWhere(t tensor.Tensor) (tensor.Tensor, error) {
+	return Nonzero(t)
+}
+
+// NonzeroTuple returns the coordinates of the n nonzero elements of t
+// as a slice of t.Dims() int tensors, each of shape (n). The tensor at
+// index i of the returned slice holds the coordinates of the nonzero
+// elements along dimension i of t. See Nonzero for more details.
+//
+// The returned tensors are useful for indexing: the tensor at index i
+// may be reshaped and used as the indices argument of Gather along
+// axis i.
+func NonzeroTuple(t tensor.Tensor) ([]tensor.Tensor, error) {
+	coords, err := nonzeroCoords(t)
+	if err != nil {
+		return nil, fmt.Errorf("nonzeroTuple: %v", err)
+	}
+
+	out := make([]tensor.Tensor, len(t.Shape()))
+	for dim := range out {
+		backing := make([]int, len(coords))
+		for i, coord := range coords {
+			backing[i] = coord[dim]
+		}
+		out[dim] = tensor.NewDense(
+			tensor.Int,
+			[]int{len(coords)},
+			tensor.WithBacking(backing),
+		)
+	}
+	return out, nil
+}
+
+// nonzeroCoords returns the coordinates of each nonzero element of t
+// in row-major order
+func nonzeroCoords(t tensor.Tensor) ([][]int, error) {
+	if t.Shape().IsScalar() {
+		return nil, fmt.Errorf("nonzeroCoords: cannot compute coordinates " +
+			"in a scalar tensor")
+	}
+
+	mask, err := nonzeroMask(t)
+	if err != nil {
+		return nil, fmt.Errorf("nonzeroCoords: %v", err)
+	}
+
+	shape := t.Shape()
+	strides := shape.CalcStrides()
+	coords := make([][]int, 0)
+	for i, nonzero := range mask {
+		if !nonzero {
+			continue
+		}
+
+		coord, err := tensor.Itol(i, shape, strides)
+		if err != nil {
+			return nil, fmt.Errorf("nonzeroCoords: could not compute "+
+				"coordinates of index %v: %v", i, err)
+		}
+		coords = append(coords, coord)
+	}
+	return coords, nil
+}
+
+// nonzeroMask returns a slice with an element for each element of t in
+// row-major order, which is true if the corresponding element of t is
+// nonzero (or true for bool tensors) and false otherwise.
+func nonzeroMask(t tensor.Tensor) ([]bool, error) {
+	switch {
+	case t.Dtype() == tensor.Bool:
+		return boolData(t)
+
+	case t.Dtype() == tensor.Float64:
+		data, _ := float64Data(t)
+		mask := make([]bool, len(data))
+		for i := range data {
+			mask[i] = data[i] != 0
+		}
+		return mask, nil
+
+	case t.Dtype() == tensor.Float32:
+		data, _ := float32Data(t)
+		mask := make([]bool, len(data))
+		for i := range data {
+			mask[i] = data[i] != 0
+		}
+		return mask, nil
+
+	case isIntDtype(t.Dtype()):
+		data, _ := intData(t)
+		mask := make([]bool, len(data))
+		for i := range data {
+			mask[i] = data[i] != 0
+		}
+		return mask, nil
+
+	default:
+		return nil, fmt.Errorf("nonzeroMask: unknown tensor type %v",
+			t.Dtype())
+	}
+}
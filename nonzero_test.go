@@ -0,0 +1,109 @@
+package top
+
+import (
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestNonzero(t *testing.T) {
+	ins := []tensor.Tensor{
+		tensor.NewDense(
+			tensor.Float64,
+			[]int{2, 3},
+			tensor.WithBacking([]float64{0, 1.5, 0, -2, 0, 3}),
+		),
+		tensor.NewDense(
+			tensor.Bool,
+			[]int{2, 3},
+			tensor.WithBacking([]bool{false, true, false, true, false, true}),
+		),
+		tensor.NewDense(
+			tensor.Uint16,
+			[]int{2, 3},
+			tensor.WithBacking([]uint16{0, 4, 0, 9, 0, 1}),
+		),
+		tensor.NewDense(
+			tensor.Float32,
+			[]int{2, 3},
+			tensor.WithBacking([]float32{0, 1, 0, 1, 0, 1}),
+		),
+	}
+	target := tensor.NewDense(
+		tensor.Int,
+		[]int{3, 2},
+		tensor.WithBacking([]int{0, 1, 1, 0, 1, 2}),
+	)
+	rowsTarget := tensor.NewDense(
+		tensor.Int,
+		[]int{3},
+		tensor.WithBacking([]int{0, 1, 1}),
+	)
+	colsTarget := tensor.NewDense(
+		tensor.Int,
+		[]int{3},
+		tensor.WithBacking([]int{1, 0, 2}),
+	)
+
+	for _, in := range ins {
+		out, err := Nonzero(in)
+		if err != nil {
+			t.Error(err)
+		}
+		if !out.Eq(target) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+		}
+
+		tuple, err := NonzeroTuple(in)
+		if err != nil {
+			t.Error(err)
+		}
+		if len(tuple) != 2 {
+			t.Fatalf("expected 2 index tensors but got %v", len(tuple))
+		}
+		if !tuple[0].Eq(rowsTarget) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", rowsTarget, tuple[0])
+		}
+		if !tuple[1].Eq(colsTarget) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", colsTarget, tuple[1])
+		}
+	}
+
+	// Tensors without nonzero elements result in empty tensors
+	zeros := tensor.NewDense(tensor.Float64, []int{4, 2})
+	out, err := Nonzero(zeros)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Shape().Eq(tensor.Shape{0, 2}) {
+		t.Errorf("expected shape (0, 2) but got %v", out.Shape())
+	}
+}
+
+// TestNonzeroGather tests if the coordinates returned by NonzeroTuple
+// can be used to gather the nonzero elements of a tensor
+func TestNonzeroGather(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float64,
+		[]int{4},
+		tensor.WithBacking([]float64{0, 7, 0, 8}),
+	)
+	target := tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{7, 8}),
+	)
+
+	indices, err := NonzeroTuple(in)
+	if err != nil {
+		t.Error(err)
+	}
+
+	out, err := Gather(in, 0, indices[0])
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+}
@@ -0,0 +1,694 @@
+package top
+
+import (
+	"math"
+
+	"gorgonia.org/tensor"
+)
+
+// The following operations are registered by default. Elementwise
+// operations broadcast their inputs against each other following the
+// NumPy broadcasting rules and work on float64 and float32 tensors.
+// Inputs and attributes are listed for each operation:
+//
+//	Name                 Inputs         Attributes
+//	add, sub, mul, div   x, y
+//	neg, exp, log, tanh  x
+//	identity, round      x
+//	sum, mean            x              axis (may be NoAxis), keepdims
+//	maxAlong, minAlong   x              axis, keepdims
+//	gather               x, indices     axis
+//	scatterAdd           src, indices   axis, shape
+//	clamp                x              min, max
+//	where                cond, x, y
+//	softmax, logSoftmax  x              axis
+//	logSumExp            x              axis, keepdims
+//	segmentSum, ...      data, ids
+//	unsortedSegmentSum   data, ids      numSegments
+//	argsort              x              axis
+//	argmax, argmin       x              axis, keepdims
+//
+// The segment operations segmentSum, segmentMean, segmentMax and
+// segmentMin, along with their unsorted counterparts, are registered.
+// The operations round, argsort, argmax and argmin are not
+// differentiable. See StraightThrough for giving round a surrogate
+// gradient.
+func init() {
+	ops := []OpDef{
+		binaryOp("add",
+			func(x, y float64) float64 { return x + y },
+			func(x, y float64) float64 { return 1 },
+			func(x, y float64) float64 { return 1 },
+		),
+		binaryOp("sub",
+			func(x, y float64) float64 { return x - y },
+			func(x, y float64) float64 { return 1 },
+			func(x, y float64) float64 { return -1 },
+		),
+		binaryOp("mul",
+			func(x, y float64) float64 { return x * y },
+			func(x, y float64) float64 { return y },
+			func(x, y float64) float64 { return x },
+		),
+		binaryOp("div",
+			func(x, y float64) float64 { return x / y },
+			func(x, y float64) float64 { return 1 / y },
+			func(x, y float64) float64 { return -x / (y * y) },
+		),
+		unaryOp("neg",
+			func(x float64) float64 { return -x },
+			func(x, y float64) float64 { return -1 },
+		),
+		unaryOp("exp", math.Exp, func(x, y float64) float64 { return y }),
+		unaryOp("log", math.Log, func(x, y float64) float64 { return 1 / x }),
+		unaryOp("tanh", math.Tanh,
+			func(x, y float64) float64 { return 1 - y*y }),
+		unaryOp("identity",
+			func(x float64) float64 { return x },
+			func(x, y float64) float64 { return 1 },
+		),
+		{
+			Name:  "round",
+			Arity: 1,
+			Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+				error) {
+				return elementwise(func(v []float64) float64 {
+					return math.Round(v[0])
+				}, inputs[0])
+			},
+		},
+		sumOp("sum", false),
+		sumOp("mean", true),
+		extremumOp("maxAlong", MaxAlong),
+		extremumOp("minAlong", MinAlong),
+		gatherOp(),
+		scatterAddOp(),
+		clampOp(),
+		whereOp(),
+		softmaxOp("softmax", false),
+		softmaxOp("logSoftmax", true),
+		logSumExpOp(),
+		segmentOpDef("segmentSum", SegmentSum, false),
+		segmentOpDef("segmentMean", SegmentMean, false),
+		segmentOpDef("segmentMax", SegmentMax, true),
+		segmentOpDef("segmentMin", SegmentMin, true),
+		unsortedSegmentOpDef("unsortedSegmentSum", UnsortedSegmentSum, false),
+		unsortedSegmentOpDef("unsortedSegmentMean", UnsortedSegmentMean,
+			false),
+		unsortedSegmentOpDef("unsortedSegmentMax", UnsortedSegmentMax, true),
+		unsortedSegmentOpDef("unsortedSegmentMin", UnsortedSegmentMin, true),
+		{
+			Name:  "argsort",
+			Arity: 1,
+			Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+				error) {
+				axis, err := attrs.Int("axis")
+				if err != nil {
+					return nil, err
+				}
+				return Argsort(inputs[0], axis)
+			},
+		},
+		argExtremumOp("argmax", Argmax),
+		argExtremumOp("argmin", Argmin),
+	}
+
+	for _, op := range ops {
+		if err := Register(op); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// tangentAt returns the tangent of input i, or a tensor of zeros with
+// the same type and shape as the input if the tangent is nil or
+// missing
+func tangentAt(tangents, inputs []tensor.Tensor, i int) tensor.Tensor {
+	if i >= len(tangents) {
+		return zerosLike(inputs[i])
+	}
+	return orZeros(tangents[i], inputs[i])
+}
+
+// unaryOp returns the definition of an elementwise operation computing
+// y = f(x), where df computes the derivative of f given x and y
+func unaryOp(name string, f func(x float64) float64,
+	df func(x, y float64) float64) OpDef {
+	return OpDef{
+		Name:  name,
+		Arity: 1,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			return elementwise(func(v []float64) float64 {
+				return f(v[0])
+			}, inputs[0])
+		},
+		VJP: func(grad tensor.Tensor, inputs []tensor.Tensor,
+			output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+			dx, err := elementwise(func(v []float64) float64 {
+				return v[0] * df(v[1], v[2])
+			}, grad, inputs[0], output)
+			if err != nil {
+				return nil, err
+			}
+			return []tensor.Tensor{dx}, nil
+		},
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			return elementwise(func(v []float64) float64 {
+				return v[0] * df(v[1], v[2])
+			}, tangentAt(tangents, inputs, 0), inputs[0], output)
+		},
+	}
+}
+
+// binaryOp returns the definition of a broadcasting elementwise
+// operation computing f(x, y), where dfdx and dfdy compute the partial
+// derivatives of f given x and y
+func binaryOp(name string, f, dfdx, dfdy func(x, y float64) float64) OpDef {
+	return OpDef{
+		Name:  name,
+		Arity: 2,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			return elementwise(func(v []float64) float64 {
+				return f(v[0], v[1])
+			}, inputs[0], inputs[1])
+		},
+		VJP: func(grad tensor.Tensor, inputs []tensor.Tensor,
+			output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+			x, y := inputs[0], inputs[1]
+			dx, shape, err := broadcastApply(func(v []float64) float64 {
+				return v[0] * dfdx(v[1], v[2])
+			}, grad, x, y)
+			if err != nil {
+				return nil, err
+			}
+			dy, _, err := broadcastApply(func(v []float64) float64 {
+				return v[0] * dfdy(v[1], v[2])
+			}, grad, x, y)
+			if err != nil {
+				return nil, err
+			}
+
+			return []tensor.Tensor{
+				unbroadcast(x.Dtype(), dx, shape, x.Shape()),
+				unbroadcast(y.Dtype(), dy, shape, y.Shape()),
+			}, nil
+		},
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			return elementwise(func(v []float64) float64 {
+				return v[0]*dfdx(v[2], v[3]) + v[1]*dfdy(v[2], v[3])
+			}, tangentAt(tangents, inputs, 0), tangentAt(tangents, inputs, 1),
+				inputs[0], inputs[1])
+		},
+	}
+}
+
+// sumOp returns the definition of the sum, or of the mean if mean is
+// true
+func sumOp(name string, mean bool) OpDef {
+	return OpDef{
+		Name:  name,
+		Arity: 1,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			axis, keepdims, err := axisAttrs(attrs)
+			if err != nil {
+				return nil, err
+			}
+			return sumAlong(inputs[0], axis, keepdims, mean)
+		},
+		VJP: func(grad tensor.Tensor, inputs []tensor.Tensor,
+			output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+			axis, _, err := axisAttrs(attrs)
+			if err != nil {
+				return nil, err
+			}
+			dx, err := sumAlongB(grad, inputs[0].Shape(), axis, mean)
+			if err != nil {
+				return nil, err
+			}
+			return []tensor.Tensor{dx}, nil
+		},
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			axis, keepdims, err := axisAttrs(attrs)
+			if err != nil {
+				return nil, err
+			}
+			return sumAlong(tangentAt(tangents, inputs, 0), axis, keepdims,
+				mean)
+		},
+	}
+}
+
+// extremumOp returns the definition of MaxAlong or MinAlong, whose
+// output is the extreme values only
+func extremumOp(name string, f func(tensor.Tensor, int, bool) (tensor.Tensor,
+	tensor.Tensor, error)) OpDef {
+	return OpDef{
+		Name:  name,
+		Arity: 1,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			axis, keepdims, err := axisAttrs(attrs)
+			if err != nil {
+				return nil, err
+			}
+			values, _, err := f(inputs[0], axis, keepdims)
+			return values, err
+		},
+		VJP: func(grad tensor.Tensor, inputs []tensor.Tensor,
+			output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+			axis, keepdims, err := axisAttrs(attrs)
+			if err != nil {
+				return nil, err
+			}
+			_, indices, err := f(inputs[0], axis, keepdims)
+			if err != nil {
+				return nil, err
+			}
+
+			dx, err := MaxAlongB(grad, indices, inputs[0].Shape(), axis)
+			if err != nil {
+				return nil, err
+			}
+			return []tensor.Tensor{dx}, nil
+		},
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			axis, _, err := axisAttrs(attrs)
+			if err != nil {
+				return nil, err
+			}
+			_, indices, err := f(inputs[0], axis, true)
+			if err != nil {
+				return nil, err
+			}
+
+			out, err := Gather(tangentAt(tangents, inputs, 0), axis, indices)
+			if err != nil {
+				return nil, err
+			}
+			return reshape(out, output.Shape())
+		},
+	}
+}
+
+// gatherOp returns the definition of Gather
+func gatherOp() OpDef {
+	return OpDef{
+		Name:  "gather",
+		Arity: 2,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			axis, err := attrs.Int("axis")
+			if err != nil {
+				return nil, err
+			}
+			return Gather(inputs[0], axis, inputs[1])
+		},
+		VJP: func(grad tensor.Tensor, inputs []tensor.Tensor,
+			output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+			axis, err := attrs.Int("axis")
+			if err != nil {
+				return nil, err
+			}
+			dx, err := ScatterAdd(grad, axis, inputs[1], inputs[0].Shape())
+			if err != nil {
+				return nil, err
+			}
+			return []tensor.Tensor{dx, nil}, nil
+		},
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			axis, err := attrs.Int("axis")
+			if err != nil {
+				return nil, err
+			}
+			return Gather(tangentAt(tangents, inputs, 0), axis, inputs[1])
+		},
+	}
+}
+
+// softmaxOp returns the definition of Softmax, or of LogSoftmax if log
+// is true
+func softmaxOp(name string, log bool) OpDef {
+	return OpDef{
+		Name:  name,
+		Arity: 1,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			axis, err := attrs.Int("axis")
+			if err != nil {
+				return nil, err
+			}
+			return softmaxAlong(inputs[0], axis, log)
+		},
+		VJP: func(grad tensor.Tensor, inputs []tensor.Tensor,
+			output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+			axis, err := attrs.Int("axis")
+			if err != nil {
+				return nil, err
+			}
+
+			var dx tensor.Tensor
+			if log {
+				dx, err = LogSoftmaxB(grad, output, axis)
+			} else {
+				dx, err = SoftmaxB(grad, output, axis)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return []tensor.Tensor{dx}, nil
+		},
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			axis, err := attrs.Int("axis")
+			if err != nil {
+				return nil, err
+			}
+			tangent := tangentAt(tangents, inputs, 0)
+
+			// The Jacobian of the softmax is symmetric
+			if !log {
+				return SoftmaxB(tangent, output, axis)
+			}
+
+			// d(log softmax) = dx - sum(softmax * dx)
+			weighted, err := elementwise(func(v []float64) float64 {
+				return math.Exp(v[0]) * v[1]
+			}, output, tangent)
+			if err != nil {
+				return nil, err
+			}
+			sum, err := sumAlong(weighted, axis, true, false)
+			if err != nil {
+				return nil, err
+			}
+			return elementwise(func(v []float64) float64 {
+				return v[0] - v[1]
+			}, tangent, sum)
+		},
+	}
+}
+
+// logSumExpOp returns the definition of LogSumExp
+func logSumExpOp() OpDef {
+	return OpDef{
+		Name:  "logSumExp",
+		Arity: 1,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			axis, keepdims, err := axisAttrs(attrs)
+			if err != nil {
+				return nil, err
+			}
+			return LogSumExp(inputs[0], axis, keepdims)
+		},
+		VJP: func(grad tensor.Tensor, inputs []tensor.Tensor,
+			output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+			axis, err := attrs.Int("axis")
+			if err != nil {
+				return nil, err
+			}
+			dx, err := LogSumExpB(grad, inputs[0], output, axis)
+			if err != nil {
+				return nil, err
+			}
+			return []tensor.Tensor{dx}, nil
+		},
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			axis, keepdims, err := axisAttrs(attrs)
+			if err != nil {
+				return nil, err
+			}
+
+			// d(logSumExp) = sum(softmax * dx)
+			probs, err := Softmax(inputs[0], axis)
+			if err != nil {
+				return nil, err
+			}
+			weighted, err := elementwise(func(v []float64) float64 {
+				return v[0] * v[1]
+			}, probs, tangentAt(tangents, inputs, 0))
+			if err != nil {
+				return nil, err
+			}
+			return sumAlong(weighted, axis, keepdims, false)
+		},
+	}
+}
+
+// scatterAddOp returns the definition of ScatterAdd
+func scatterAddOp() OpDef {
+	attrsOf := func(attrs Attrs) (int, tensor.Shape, error) {
+		axis, err := attrs.Int("axis")
+		if err != nil {
+			return 0, nil, err
+		}
+		shape, err := attrs.Shape("shape")
+		return axis, shape, err
+	}
+
+	return OpDef{
+		Name:  "scatterAdd",
+		Arity: 2,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			axis, shape, err := attrsOf(attrs)
+			if err != nil {
+				return nil, err
+			}
+			return ScatterAdd(inputs[0], axis, inputs[1], shape)
+		},
+		VJP: func(grad tensor.Tensor, inputs []tensor.Tensor,
+			output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+			axis, _, err := attrsOf(attrs)
+			if err != nil {
+				return nil, err
+			}
+			dsrc, err := Gather(grad, axis, inputs[1])
+			if err != nil {
+				return nil, err
+			}
+			return []tensor.Tensor{dsrc, nil}, nil
+		},
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			axis, shape, err := attrsOf(attrs)
+			if err != nil {
+				return nil, err
+			}
+			return ScatterAdd(tangentAt(tangents, inputs, 0), axis, inputs[1],
+				shape)
+		},
+	}
+}
+
+// clampOp returns the definition of Clamp
+func clampOp() OpDef {
+	// mask returns the mask of ClampB multiplied elementwise by t
+	mask := func(t, x tensor.Tensor, attrs Attrs) (tensor.Tensor, error) {
+		min, err := attrs.Value("min")
+		if err != nil {
+			return nil, err
+		}
+		max, err := attrs.Value("max")
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := ClampB(x, min, max)
+		if err != nil {
+			return nil, err
+		}
+		return elementwise(func(v []float64) float64 {
+			return v[0] * v[1]
+		}, t, m)
+	}
+
+	return OpDef{
+		Name:  "clamp",
+		Arity: 1,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			min, err := attrs.Value("min")
+			if err != nil {
+				return nil, err
+			}
+			max, err := attrs.Value("max")
+			if err != nil {
+				return nil, err
+			}
+			return Clamp(inputs[0], min, max)
+		},
+		VJP: func(grad tensor.Tensor, inputs []tensor.Tensor,
+			output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+			dx, err := mask(grad, inputs[0], attrs)
+			if err != nil {
+				return nil, err
+			}
+			return []tensor.Tensor{dx}, nil
+		},
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			return mask(tangentAt(tangents, inputs, 0), inputs[0], attrs)
+		},
+	}
+}
+
+// whereOp returns the definition of Where, whose first input is the
+// condition tensor
+func whereOp() OpDef {
+	return OpDef{
+		Name:  "where",
+		Arity: 3,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			return Where(inputs[0], inputs[1], inputs[2])
+		},
+		VJP: func(grad tensor.Tensor, inputs []tensor.Tensor,
+			output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+			dx, dy, err := WhereB(grad, inputs[0], inputs[1].Shape(),
+				inputs[2].Shape())
+			if err != nil {
+				return nil, err
+			}
+			return []tensor.Tensor{nil, dx, dy}, nil
+		},
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			return Where(inputs[0], tangentAt(tangents, inputs, 1),
+				tangentAt(tangents, inputs, 2))
+		},
+	}
+}
+
+// segmentOpDef returns the definition of a sorted segment reduction. If
+// extremum is true, the reduction is a maximum or minimum.
+func segmentOpDef(name string, f func(tensor.Tensor,
+	tensor.Tensor) (tensor.Tensor, error), extremum bool) OpDef {
+	return OpDef{
+		Name:  name,
+		Arity: 2,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			return f(inputs[0], inputs[1])
+		},
+		VJP: segmentVJP(name, extremum),
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			tangent := tangentAt(tangents, inputs, 0)
+			if extremum {
+				return segmentExtremumJVP(tangent, inputs, output)
+			}
+			return f(tangent, inputs[1])
+		},
+	}
+}
+
+// unsortedSegmentOpDef returns the definition of an unsorted segment
+// reduction. If extremum is true, the reduction is a maximum or
+// minimum.
+func unsortedSegmentOpDef(name string, f func(tensor.Tensor, tensor.Tensor,
+	int) (tensor.Tensor, error), extremum bool) OpDef {
+	return OpDef{
+		Name:  name,
+		Arity: 2,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			numSegments, err := attrs.Int("numSegments")
+			if err != nil {
+				return nil, err
+			}
+			return f(inputs[0], inputs[1], numSegments)
+		},
+		VJP: segmentVJP(name, extremum),
+		JVP: func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			tangent := tangentAt(tangents, inputs, 0)
+			if extremum {
+				return segmentExtremumJVP(tangent, inputs, output)
+			}
+			return f(tangent, inputs[1], output.Shape()[0])
+		},
+	}
+}
+
+// segmentVJP returns the vector-Jacobian product of a segment
+// reduction. If extremum is true, the reduction is a maximum or
+// minimum, otherwise it is a sum or mean depending on name.
+func segmentVJP(name string, extremum bool) VJPFunc {
+	return func(grad tensor.Tensor, inputs []tensor.Tensor,
+		output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+		var ddata tensor.Tensor
+		var err error
+		switch {
+		case extremum:
+			ddata, err = SegmentMaxB(grad, inputs[0], inputs[1], output)
+		case name == "segmentMean" || name == "unsortedSegmentMean":
+			ddata, err = SegmentMeanB(grad, inputs[1])
+		default:
+			ddata, err = SegmentSumB(grad, inputs[1])
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []tensor.Tensor{ddata, nil}, nil
+	}
+}
+
+// segmentExtremumJVP returns the Jacobian-vector product of a segment
+// maximum or minimum, which sums the tangent over the extremal elements
+// of each segment, dividing ties evenly
+func segmentExtremumJVP(tangent tensor.Tensor, inputs []tensor.Tensor,
+	output tensor.Tensor) (tensor.Tensor, error) {
+	ones := full(tangent.Dtype(), output.Shape(), 1)
+	weights, err := SegmentMaxB(ones, inputs[0], inputs[1], output)
+	if err != nil {
+		return nil, err
+	}
+	weighted, err := elementwise(func(v []float64) float64 {
+		return v[0] * v[1]
+	}, tangent, weights)
+	if err != nil {
+		return nil, err
+	}
+	return UnsortedSegmentSum(weighted, inputs[1], output.Shape()[0])
+}
+
+// argExtremumOp returns the definition of Argmax or Argmin, breaking
+// ties by choosing the first extreme element
+func argExtremumOp(name string, f func(tensor.Tensor, int, bool,
+	TieBreak) (tensor.Tensor, error)) OpDef {
+	return OpDef{
+		Name:  name,
+		Arity: 1,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			axis, keepdims, err := axisAttrs(attrs)
+			if err != nil {
+				return nil, err
+			}
+			return f(inputs[0], axis, keepdims, TieFirst)
+		},
+	}
+}
+
+// axisAttrs returns the axis and keepdims attributes
+func axisAttrs(attrs Attrs) (int, bool, error) {
+	axis, err := attrs.Int("axis")
+	if err != nil {
+		return 0, false, err
+	}
+	keepdims, err := attrs.Bool("keepdims")
+	if err != nil {
+		return 0, false, err
+	}
+	return axis, keepdims, nil
+}
@@ -0,0 +1,199 @@
+package top
+
+import (
+	"math"
+	"sort"
+)
+
+// radixThreshold is the length of a slice at and above which argSort
+// uses a radix sort rather than a comparison sort, if the slice type
+// supports radix sorting
+const radixThreshold int = 256
+
+// radixKeys returns, for each element of s, an unsigned integer key
+// whose natural order is the same as the order of the elements of s,
+// along with the number of low-order bytes which may differ between
+// keys. If s does not support radix sorting, ok is false.
+//
+// Radix sorting is supported for the slice types used by Argsort.
+// Since float32Slice does not order NaN values consistently, radix
+// sorting is not supported for a float32Slice holding NaN values.
+func radixKeys(s sort.Interface) (keys []uint64, bytes int, ok bool) {
+	switch s := s.(type) {
+	case sort.Float64Slice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			keys[i] = float64Key(v)
+		}
+		return keys, 8, true
+
+	case float32Slice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			if v != v {
+				return nil, 0, false
+			}
+			keys[i] = float64Key(float64(v))
+		}
+		return keys, 8, true
+
+	case sort.IntSlice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			keys[i] = signedKey(int64(v), 64)
+		}
+		return keys, 8, true
+
+	case int8Slice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			keys[i] = signedKey(int64(v), 8)
+		}
+		return keys, 1, true
+
+	case int16Slice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			keys[i] = signedKey(int64(v), 16)
+		}
+		return keys, 2, true
+
+	case int32Slice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			keys[i] = signedKey(int64(v), 32)
+		}
+		return keys, 4, true
+
+	case int64Slice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			keys[i] = signedKey(v, 64)
+		}
+		return keys, 8, true
+
+	case uintSlice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			keys[i] = uint64(v)
+		}
+		return keys, 8, true
+
+	case uint8Slice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			keys[i] = uint64(v)
+		}
+		return keys, 1, true
+
+	case uint16Slice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			keys[i] = uint64(v)
+		}
+		return keys, 2, true
+
+	case uint32Slice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			keys[i] = uint64(v)
+		}
+		return keys, 4, true
+
+	case uint64Slice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			keys[i] = v
+		}
+		return keys, 8, true
+
+	case boolSlice:
+		keys = make([]uint64, len(s))
+		for i, v := range s {
+			if v {
+				keys[i] = 1
+			}
+		}
+		return keys, 1, true
+
+	default:
+		return nil, 0, false
+	}
+}
+
+// signedKey returns an unsigned key for a signed integer of the
+// argument number of bits, such that keys are ordered in the same
+// way as the integers. This is done by flipping the sign bit.
+func signedKey(v int64, bits uint) uint64 {
+	mask := uint64(math.MaxUint64) >> (64 - bits)
+	return (uint64(v) ^ (1 << (bits - 1))) & mask
+}
+
+// float64Key returns an unsigned key for a float64, such that keys are
+// ordered in the same way as sort.Float64Slice orders floats. Positive
+// floats have their sign bit set, and negative floats have all bits
+// flipped so that larger magnitudes result in smaller keys. Negative
+// zero is treated as positive zero since the two are equal, and all
+// NaNs have the smallest key since sort.Float64Slice orders NaNs
+// before all other values.
+func float64Key(v float64) uint64 {
+	if v != v {
+		return 0
+	}
+	if v == 0 {
+		v = 0 // Negative zero
+	}
+
+	bits := math.Float64bits(v)
+	if bits>>63 == 1 {
+		return ^bits
+	}
+	return bits | 1<<63
+}
+
+// radixArgsort returns the indices that would stably sort keys, using
+// a least significant digit radix sort on the lowest bytes bytes of
+// the keys. Passes over bytes which are the same for all keys are
+// skipped.
+func radixArgsort(keys []uint64, bytes int) []int {
+	n := len(keys)
+	ind := make([]int, n)
+	for i := range ind {
+		ind[i] = i
+	}
+	if n == 0 {
+		return ind
+	}
+	buf := make([]int, n)
+
+	for b := 0; b < bytes; b++ {
+		shift := uint(8 * b)
+
+		var count [256]int
+		for _, key := range keys {
+			count[(key>>shift)&0xff]++
+		}
+
+		// Skip this pass if every key has the same byte
+		if count[(keys[0]>>shift)&0xff] == n {
+			continue
+		}
+
+		// Compute the starting position of each digit in the output
+		pos := 0
+		for digit, c := range count {
+			count[digit] = pos
+			pos += c
+		}
+
+		// Stably distribute the indices by the current byte
+		for _, i := range ind {
+			digit := (keys[i] >> shift) & 0xff
+			buf[count[digit]] = i
+			count[digit]++
+		}
+		ind, buf = buf, ind
+	}
+
+	return ind
+}
@@ -0,0 +1,89 @@
+package top
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+// stableArgSort returns the indices that would stably sort s using a
+// comparison sort, which the radix sort must match
+func stableArgSort(s sort.Interface) []int {
+	a := newArgSorter(s)
+	sort.Stable(a)
+	return a.ind
+}
+
+// TestRadixArgsort tests if the radix sort used by argSort for large
+// slices results in the same indices as a stable comparison sort
+func TestRadixArgsort(t *testing.T) {
+	const numTests int = 10
+	const size int = 5 * radixThreshold
+	rand.Seed(time.Now().UnixNano())
+
+	special := []float64{
+		math.NaN(), math.Inf(1), math.Inf(-1), 0, math.Copysign(0, -1),
+		math.SmallestNonzeroFloat64, -math.MaxFloat64,
+	}
+
+	for i := 0; i < numTests; i++ {
+		f64 := make(sort.Float64Slice, size)
+		f32 := make(float32Slice, size)
+		ints := make(sort.IntSlice, size)
+		i8 := make(int8Slice, size)
+		i16 := make(int16Slice, size)
+		i32 := make(int32Slice, size)
+		i64 := make(int64Slice, size)
+		u := make(uintSlice, size)
+		u8 := make(uint8Slice, size)
+		u16 := make(uint16Slice, size)
+		u32 := make(uint32Slice, size)
+		u64 := make(uint64Slice, size)
+		b := make(boolSlice, size)
+
+		for j := 0; j < size; j++ {
+			// Use few distinct values so that many elements tie
+			v := rand.Int63n(64) - 32
+			if rand.Intn(2) == 0 {
+				v *= math.MaxInt32
+			}
+
+			f64[j] = float64(v) / 3
+			if rand.Intn(10) == 0 {
+				f64[j] = special[rand.Intn(len(special))]
+			}
+			f32[j] = float32(v) / 3
+			if rand.Intn(10) == 0 && !math.IsNaN(f64[j]) {
+				f32[j] = float32(f64[j])
+			}
+			ints[j] = int(v)
+			i8[j] = int8(v)
+			i16[j] = int16(v)
+			i32[j] = int32(v)
+			i64[j] = v
+			u[j] = uint(v)
+			u8[j] = uint8(v)
+			u16[j] = uint16(v)
+			u32[j] = uint32(v)
+			u64[j] = uint64(v)
+			b[j] = v > 0
+		}
+
+		slices := []sort.Interface{
+			f64, f32, ints, i8, i16, i32, i64, u, u8, u16, u32, u64, b,
+		}
+		for _, s := range slices {
+			target := stableArgSort(s)
+			out := argSort(s)
+			for j := range target {
+				if target[j] != out[j] {
+					t.Errorf("%T: expected index %v at position %v but "+
+						"got %v", s, target[j], j, out[j])
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// RankMethod determines how Rank assigns ranks to tied elements
+type RankMethod int
+
+const (
+	// RankAverage assigns each tied element the average of the ranks
+	// that would have been assigned to the tied elements
+	RankAverage RankMethod = iota
+
+	// RankMin assigns each tied element the minimum of the ranks that
+	// would have been assigned to the tied elements
+	RankMin
+
+	// RankMax assigns each tied element the maximum of the ranks that
+	// would have been assigned to the tied elements
+	RankMax
+
+	// RankDense is like RankMin, but the rank of the next largest
+	// element after a group of tied elements is one more than the
+	// rank of the tied elements, so that ranks are consecutive
+	RankDense
+
+	// RankOrdinal assigns each element a distinct rank, with tied
+	// elements ranked in the order in which they occur
+	RankOrdinal
+)
+
+// Rank returns the ranks of the elements of t along axis, where the
+// smallest element along each row has rank 1. Ties are handled
+// according to method. If method is RankAverage, the ranks are
+// returned in a tensor of type tensor.Float64, since average ranks may
+// be fractional. Otherwise, the ranks are returned in a tensor of type
+// tensor.Int.
+//
+// Rank works on tensors of any type supported by Argsort.
+//
+// This implementation follows SciPy's rankdata function. See SciPy's
+// documentation for more details and usage:
+// https://docs.scipy.org/doc/scipy/reference/generated/scipy.stats.rankdata.html
+func Rank(t tensor.Tensor, axis int, method RankMethod) (tensor.Tensor,
+	error) {
+	switch method {
+	case RankAverage, RankMin, RankMax, RankDense, RankOrdinal:
+	default:
+		return nil, fmt.Errorf("rank: unknown rank method %v", method)
+	}
+
+	sortedInd, err := Argsort(t, axis)
+	if err != nil {
+		return nil, fmt.Errorf("rank: %v", err)
+	}
+	args, _ := intData(sortedInd)
+
+	cmp, err := comparer(t, true)
+	if err != nil {
+		return nil, fmt.Errorf("rank: %v", err)
+	}
+
+	shape := t.Shape()
+	starts, stride := axisRows(shape, axis)
+	ranks := make([]float64, shape.TotalSize())
+	for _, start := range starts {
+		// Walk through the row in sorted order, assigning ranks to each
+		// group of tied elements
+		dense := 0
+		for i := 0; i < shape[axis]; {
+			first := start + args[start+i*stride]*stride
+
+			// Find the end of the group of tied elements
+			j := i + 1
+			for j < shape[axis] {
+				next := start + args[start+j*stride]*stride
+				if cmp(first, next) != 0 {
+					break
+				}
+				j++
+			}
+			dense++
+
+			for k := i; k < j; k++ {
+				var rank float64
+				switch method {
+				case RankAverage:
+					rank = float64(i+j+1) / 2
+				case RankMin:
+					rank = float64(i + 1)
+				case RankMax:
+					rank = float64(j)
+				case RankDense:
+					rank = float64(dense)
+				case RankOrdinal:
+					rank = float64(k + 1)
+				}
+				ranks[start+args[start+k*stride]*stride] = rank
+			}
+			i = j
+		}
+	}
+
+	if method == RankAverage {
+		return newTensor(shape.Clone(), ranks), nil
+	}
+
+	intRanks := make([]int, len(ranks))
+	for i := range ranks {
+		intRanks[i] = int(ranks[i])
+	}
+	return newTensor(shape.Clone(), intRanks), nil
+}
@@ -0,0 +1,65 @@
+package top
+
+import (
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestRank(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 4},
+		tensor.WithBacking([]float64{0, 2, 3, 2, 7, 7, 7, 1}),
+	)
+
+	average := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 4},
+		tensor.WithBacking([]float64{1, 2.5, 4, 2.5, 3, 3, 3, 1}),
+	)
+	out, err := Rank(in, 1, RankAverage)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(average) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", average, out)
+	}
+
+	methods := []RankMethod{RankMin, RankMax, RankDense, RankOrdinal}
+	targets := [][]int{
+		{1, 2, 4, 2, 2, 2, 2, 1},
+		{1, 3, 4, 3, 4, 4, 4, 1},
+		{1, 2, 3, 2, 2, 2, 2, 1},
+		{1, 2, 4, 3, 2, 3, 4, 1},
+	}
+	for i, method := range methods {
+		target := tensor.NewDense(
+			tensor.Int,
+			[]int{2, 4},
+			tensor.WithBacking(targets[i]),
+		)
+
+		out, err := Rank(in, 1, method)
+		if err != nil {
+			t.Error(err)
+		}
+		if !out.Eq(target) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+		}
+	}
+
+	// Ranks along the first axis
+	axis0 := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 4},
+		tensor.WithBacking([]int{1, 1, 1, 2, 2, 2, 2, 1}),
+	)
+	out, err = Rank(in, 0, RankOrdinal)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(axis0) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", axis0, out)
+	}
+}
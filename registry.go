@@ -0,0 +1,185 @@
+package top
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"gorgonia.org/tensor"
+)
+
+// Attrs holds the non-tensor arguments of an operation, such as the
+// axis along which the operation is computed, keyed by name
+type Attrs map[string]interface{}
+
+// Value returns the attribute called name, or an error if the attribute
+// does not exist
+func (a Attrs) Value(name string) (interface{}, error) {
+	v, ok := a[name]
+	if !ok {
+		return nil, fmt.Errorf("attrs: missing attribute %q", name)
+	}
+	return v, nil
+}
+
+// Int returns the attribute called name as an int. The attribute may be
+// of any integer type.
+func (a Attrs) Int(name string) (int, error) {
+	v, err := a.Value(name)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := anyIntToInt(v)
+	if err != nil {
+		return 0, fmt.Errorf("attrs: attribute %q of type %T is not an "+
+			"integer", name, v)
+	}
+	return i, nil
+}
+
+// Bool returns the attribute called name as a bool
+func (a Attrs) Bool(name string) (bool, error) {
+	v, err := a.Value(name)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("attrs: attribute %q of type %T is not a "+
+			"bool", name, v)
+	}
+	return b, nil
+}
+
+// Shape returns the attribute called name as a tensor.Shape. The
+// attribute may be either a tensor.Shape or a []int.
+func (a Attrs) Shape(name string) (tensor.Shape, error) {
+	v, err := a.Value(name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch s := v.(type) {
+	case tensor.Shape:
+		return s, nil
+	case []int:
+		return tensor.Shape(s), nil
+	default:
+		return nil, fmt.Errorf("attrs: attribute %q of type %T is not a "+
+			"shape", name, v)
+	}
+}
+
+// ForwardFunc computes the output of an operation from its tensor
+// inputs and attributes
+type ForwardFunc func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+	error)
+
+// VJPFunc computes the vector-Jacobian product of an operation. Given
+// the gradient grad of some function with respect to the output of the
+// operation, along with the inputs, output, and attributes of the
+// forward pass, a VJPFunc returns the gradient of that function with
+// respect to each input. The gradient of an input with respect to
+// which the operation is not differentiable, such as an index tensor,
+// is nil.
+type VJPFunc func(grad tensor.Tensor, inputs []tensor.Tensor,
+	output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error)
+
+// JVPFunc computes the Jacobian-vector product of an operation. Given a
+// tangent for each input, along with the inputs, output, and attributes
+// of the forward pass, a JVPFunc returns the tangent of the output. A
+// nil tangent is treated as a tangent of zeros, and tangents of inputs
+// with respect to which the operation is not differentiable are
+// ignored.
+type JVPFunc func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+	attrs Attrs) (tensor.Tensor, error)
+
+// OpDef defines an operation which can be differentiated generically.
+// Operations which are not differentiable have nil VJP and JVP
+// functions.
+type OpDef struct {
+	Name    string
+	Arity   int // Number of tensor inputs
+	Forward ForwardFunc
+	VJP     VJPFunc
+	JVP     JVPFunc
+}
+
+// Differentiable returns whether the operation has a vector-Jacobian
+// product
+func (o OpDef) Differentiable() bool {
+	return o.VJP != nil
+}
+
+// registry holds all registered operations keyed by name
+var registry = struct {
+	sync.RWMutex
+	ops map[string]OpDef
+}{ops: make(map[string]OpDef)}
+
+// Register adds an operation to the registry so that it can be found by
+// name with Lookup. An error is returned if the operation has no name,
+// has no forward function, or has the same name as an operation which
+// is already registered.
+func Register(op OpDef) error {
+	if op.Name == "" {
+		return fmt.Errorf("register: operation must have a name")
+	}
+	if op.Forward == nil {
+		return fmt.Errorf("register: operation %q must have a forward "+
+			"function", op.Name)
+	}
+	if op.Arity < 0 {
+		return fmt.Errorf("register: operation %q must have non-negative "+
+			"arity but got %v", op.Name, op.Arity)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.ops[op.Name]; ok {
+		return fmt.Errorf("register: operation %q already registered",
+			op.Name)
+	}
+	registry.ops[op.Name] = op
+	return nil
+}
+
+// Lookup returns the registered operation called name, and whether such
+// an operation exists
+func Lookup(name string) (OpDef, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	op, ok := registry.ops[name]
+	return op, ok
+}
+
+// Registered returns the names of all registered operations in sorted
+// order
+func Registered() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	names := make([]string, 0, len(registry.ops))
+	for name := range registry.ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply looks up the operation called name and computes its output on
+// the argument inputs and attributes
+func Apply(name string, inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+	error) {
+	op, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("apply: unknown operation %q", name)
+	}
+	if len(inputs) != op.Arity {
+		return nil, fmt.Errorf("apply: operation %q expects %v inputs but "+
+			"got %v", name, op.Arity, len(inputs))
+	}
+	return op.Forward(inputs, attrs)
+}
@@ -0,0 +1,268 @@
+package top
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+// registryCase is an application of a registered operation used to
+// test its vector-Jacobian and Jacobian-vector products
+type registryCase struct {
+	name   string
+	inputs []tensor.Tensor
+	attrs  Attrs
+}
+
+// registryCases returns a test case for each differentiable builtin
+// operation
+func registryCases() []registryCase {
+	x := randTensor([]int{3, 4}, 0.5, 2)
+	y := randTensor([]int{1, 4}, 0.5, 2)
+	idx := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 4},
+		tensor.WithBacking([]int{0, 2, 1, 1, 2, 2, 0, 1}),
+	)
+	cond := tensor.NewDense(
+		tensor.Bool,
+		[]int{4},
+		tensor.WithBacking([]bool{true, false, false, true}),
+	)
+	ids := tensor.NewDense(
+		tensor.Int,
+		[]int{3},
+		tensor.WithBacking([]int{0, 0, 2}),
+	)
+	axisKeep := Attrs{"axis": 1, "keepdims": true}
+	axisDrop := Attrs{"axis": 0, "keepdims": false}
+
+	return []registryCase{
+		{"add", []tensor.Tensor{x, y}, nil},
+		{"sub", []tensor.Tensor{x, y}, nil},
+		{"mul", []tensor.Tensor{x, y}, nil},
+		{"div", []tensor.Tensor{x, y}, nil},
+		{"neg", []tensor.Tensor{x}, nil},
+		{"exp", []tensor.Tensor{x}, nil},
+		{"log", []tensor.Tensor{x}, nil},
+		{"tanh", []tensor.Tensor{x}, nil},
+		{"identity", []tensor.Tensor{x}, nil},
+		{"sum", []tensor.Tensor{x}, axisKeep},
+		{"sum", []tensor.Tensor{x}, Attrs{"axis": NoAxis, "keepdims": false}},
+		{"mean", []tensor.Tensor{x}, axisDrop},
+		{"maxAlong", []tensor.Tensor{x}, axisKeep},
+		{"minAlong", []tensor.Tensor{x}, axisDrop},
+		{"gather", []tensor.Tensor{x, idx}, Attrs{"axis": 0}},
+		{"scatterAdd", []tensor.Tensor{randTensor([]int{2, 4}, -1, 1), idx},
+			Attrs{"axis": 0, "shape": []int{3, 4}}},
+		{"clamp", []tensor.Tensor{x}, Attrs{"min": 0.8, "max": 1.5}},
+		{"where", []tensor.Tensor{cond, x, y}, nil},
+		{"softmax", []tensor.Tensor{x}, Attrs{"axis": 1}},
+		{"softmax", []tensor.Tensor{x}, Attrs{"axis": 0}},
+		{"logSoftmax", []tensor.Tensor{x}, Attrs{"axis": 1}},
+		{"logSumExp", []tensor.Tensor{x}, axisKeep},
+		{"logSumExp", []tensor.Tensor{x}, axisDrop},
+		{"segmentSum", []tensor.Tensor{x, ids}, nil},
+		{"segmentMean", []tensor.Tensor{x, ids}, nil},
+		{"segmentMax", []tensor.Tensor{x, ids}, nil},
+		{"segmentMin", []tensor.Tensor{x, ids}, nil},
+		{"unsortedSegmentSum", []tensor.Tensor{x, ids},
+			Attrs{"numSegments": 4}},
+		{"unsortedSegmentMean", []tensor.Tensor{x, ids},
+			Attrs{"numSegments": 3}},
+		{"unsortedSegmentMax", []tensor.Tensor{x, ids},
+			Attrs{"numSegments": 3}},
+		{"unsortedSegmentMin", []tensor.Tensor{x, ids},
+			Attrs{"numSegments": 3}},
+	}
+}
+
+// randTensor returns a float64 tensor of the argument shape with
+// elements drawn uniformly from [lo, hi)
+func randTensor(shape []int, lo, hi float64) tensor.Tensor {
+	data := make([]float64, tensor.ProdInts(shape))
+	for i := range data {
+		data[i] = lo + rand.Float64()*(hi-lo)
+	}
+	return tensor.NewDense(tensor.Float64, shape, tensor.WithBacking(data))
+}
+
+// dot returns the sum of the elementwise product of two float64 tensors
+func dot(a, b tensor.Tensor) float64 {
+	aData, _ := toFloat64Data(a)
+	bData, _ := toFloat64Data(b)
+	total := 0.0
+	for i := range aData {
+		total += aData[i] * bData[i]
+	}
+	return total
+}
+
+// TestRegistryAdjoint tests that the vector-Jacobian and Jacobian-vector
+// products of each differentiable builtin operation are adjoint, that
+// is <VJP(grad), tangent> == <grad, JVP(tangent)>
+func TestRegistryAdjoint(t *testing.T) {
+	const tol = 1e-9
+
+	for _, c := range registryCases() {
+		op, ok := Lookup(c.name)
+		if !ok {
+			t.Errorf("%v: operation not registered", c.name)
+			continue
+		}
+		if !op.Differentiable() {
+			t.Errorf("%v: expected operation to be differentiable", c.name)
+			continue
+		}
+
+		out, err := Apply(c.name, c.inputs, c.attrs)
+		if err != nil {
+			t.Errorf("%v: %v", c.name, err)
+			continue
+		}
+		grad := randTensor(out.Shape(), -1, 1)
+
+		tangents := make([]tensor.Tensor, len(c.inputs))
+		for i, in := range c.inputs {
+			if in.Dtype() == tensor.Float64 {
+				tangents[i] = randTensor(in.Shape(), -1, 1)
+			}
+		}
+
+		vjp, err := op.VJP(grad, c.inputs, out, c.attrs)
+		if err != nil {
+			t.Errorf("%v: %v", c.name, err)
+			continue
+		}
+		jvp, err := op.JVP(tangents, c.inputs, out, c.attrs)
+		if err != nil {
+			t.Errorf("%v: %v", c.name, err)
+			continue
+		}
+		if !jvp.Shape().Eq(out.Shape()) {
+			t.Errorf("%v: expected JVP of shape %v but got %v", c.name,
+				out.Shape(), jvp.Shape())
+			continue
+		}
+
+		lhs := 0.0
+		for i := range vjp {
+			if tangents[i] == nil {
+				continue
+			}
+			if vjp[i] == nil {
+				t.Errorf("%v: missing gradient of input %v", c.name, i)
+				continue
+			}
+			if !vjp[i].Shape().Eq(c.inputs[i].Shape()) {
+				t.Errorf("%v: expected gradient of shape %v but got %v",
+					c.name, c.inputs[i].Shape(), vjp[i].Shape())
+				continue
+			}
+			lhs += dot(vjp[i], tangents[i])
+		}
+		rhs := dot(grad, jvp)
+
+		if math.Abs(lhs-rhs) > tol*math.Max(1, math.Abs(lhs)) {
+			t.Errorf("%v: <VJP(grad), tangent> = %v but <grad, JVP(tangent)> "+
+				"= %v", c.name, lhs, rhs)
+		}
+	}
+}
+
+func TestRegistryForward(t *testing.T) {
+	x := tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 2},
+		tensor.WithBacking([]float32{1, 2, 3, 4}),
+	)
+	y := tensor.New(tensor.FromScalar(float32(2)))
+
+	out, err := Apply("mul", []tensor.Tensor{x, y}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 2},
+		tensor.WithBacking([]float32{2, 4, 6, 8}),
+	)
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	out, err = Apply("sum", []tensor.Tensor{x}, Attrs{"axis": 0,
+		"keepdims": false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	target = tensor.NewDense(
+		tensor.Float32,
+		[]int{2},
+		tensor.WithBacking([]float32{4, 6}),
+	)
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	if _, err := Apply("sum", []tensor.Tensor{x}, nil); err == nil {
+		t.Error("expected error for missing attributes")
+	}
+	if _, err := Apply("mul", []tensor.Tensor{x}, nil); err == nil {
+		t.Error("expected error for wrong number of inputs")
+	}
+	if _, err := Apply("unknown", nil, nil); err == nil {
+		t.Error("expected error for unknown operation")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register(OpDef{Name: "add", Arity: 2,
+		Forward: func([]tensor.Tensor, Attrs) (tensor.Tensor, error) {
+			return nil, nil
+		}}); err == nil {
+		t.Error("expected error when registering duplicate operation")
+	}
+	if err := Register(OpDef{Name: "noForward", Arity: 1}); err == nil {
+		t.Error("expected error when registering operation without " +
+			"forward function")
+	}
+
+	double := OpDef{
+		Name:  "testDouble",
+		Arity: 1,
+		Forward: func(inputs []tensor.Tensor, attrs Attrs) (tensor.Tensor,
+			error) {
+			return Apply("add", []tensor.Tensor{inputs[0], inputs[0]}, nil)
+		},
+	}
+	// The operation may have been registered by a previous run of the
+	// test with -count
+	if _, ok := Lookup(double.Name); !ok {
+		if err := Register(double); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	op, ok := Lookup("testDouble")
+	if !ok {
+		t.Fatal("expected registered operation to be found")
+	}
+	if op.Differentiable() {
+		t.Error("expected operation without VJP to not be differentiable")
+	}
+
+	found := false
+	for _, name := range Registered() {
+		found = found || name == "testDouble"
+	}
+	if !found {
+		t.Error("expected registered operation to be listed")
+	}
+
+	if op, ok := Lookup("argsort"); !ok || op.Differentiable() {
+		t.Error("expected argsort to be registered and not differentiable")
+	}
+}
@@ -0,0 +1,193 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// DiscountedReturns computes the discounted return at each step along
+// the time axis of rewards, resetting at episode boundaries:
+//
+//	G[t] = rewards[t] + gamma * (1 - dones[t]) * G[t+1]
+//
+// where the return after the last step is 0. The dones tensor must have
+// the same shape as rewards, and dones[t] indicates that the episode
+// ended at step t, so that no later rewards are included in G[t]. All
+// other axes are batch axes, such as the environment axis of a batch of
+// shape (time, env).
+//
+// DiscountedReturns works on rewards of type float64 or float32, and
+// returns a tensor of the same shape and type. The dones tensor may be
+// of type tensor.Bool, or of the type of rewards, in which case any
+// non-zero element indicates the end of an episode.
+func DiscountedReturns(rewards, dones tensor.Tensor, gamma float64,
+	axis int) (tensor.Tensor, error) {
+	done, err := checkReturnsArgs(rewards, dones, axis)
+	if err != nil {
+		return nil, fmt.Errorf("discountedReturns: %v", err)
+	}
+
+	shape := rewards.Shape()
+	starts, stride := axisRows(shape, axis)
+	n := shape[axis]
+
+	switch rewards.Dtype() {
+	case tensor.Float64:
+		r, _ := float64Data(rewards)
+		out := make([]float64, len(r))
+		for _, start := range starts {
+			g := 0.0
+			for j := n - 1; j >= 0; j-- {
+				i := start + j*stride
+				if done[i] {
+					g = 0
+				}
+				g = r[i] + gamma*g
+				out[i] = g
+			}
+		}
+		return newTensor(shape.Clone(), out), nil
+
+	default:
+		r, _ := float32Data(rewards)
+		out := make([]float32, len(r))
+		gamma := float32(gamma)
+		for _, start := range starts {
+			g := float32(0)
+			for j := n - 1; j >= 0; j-- {
+				i := start + j*stride
+				if done[i] {
+					g = 0
+				}
+				g = r[i] + gamma*g
+				out[i] = g
+			}
+		}
+		return newTensor(shape.Clone(), out), nil
+	}
+}
+
+// LambdaReturns computes the TD(λ) return at each step along the time
+// axis of rewards, resetting at episode boundaries:
+//
+//	G[t] = rewards[t] + gamma * (1 - dones[t]) *
+//		((1 - lambda) * values[t+1] + lambda * G[t+1])
+//
+// where values[t] is the estimated value of the state at step t, and
+// both values[T] and G[T] after the last step T-1 are given by
+// bootstrap. With lambda = 0 this is the one-step TD target, and with
+// lambda = 1 it is the discounted return bootstrapped from the value of
+// the state after the last step.
+//
+// The values and dones tensors must have the same shape as rewards,
+// and bootstrap must have the shape of rewards with the time axis
+// either removed or of size 1. The dones tensor is interpreted as in
+// DiscountedReturns. LambdaReturns works on tensors of type float64 or
+// float32, and returns a tensor of the same shape and type as rewards.
+func LambdaReturns(rewards, values, bootstrap, dones tensor.Tensor, gamma,
+	lambda float64, axis int) (tensor.Tensor, error) {
+	done, err := checkReturnsArgs(rewards, dones, axis)
+	if err != nil {
+		return nil, fmt.Errorf("lambdaReturns: %v", err)
+	}
+
+	shape := rewards.Shape()
+	if values.Dtype() != rewards.Dtype() ||
+		bootstrap.Dtype() != rewards.Dtype() {
+		return nil, fmt.Errorf("lambdaReturns: expected values and "+
+			"bootstrap of type %v but got values=%v and bootstrap=%v",
+			rewards.Dtype(), values.Dtype(), bootstrap.Dtype())
+	}
+	if !equalShapes(values.Shape(), shape) {
+		return nil, fmt.Errorf("lambdaReturns: expected values of shape "+
+			"%v but got %v", shape, values.Shape())
+	}
+	if !equalShapes(bootstrap.Shape(), reducedShape(shape, axis, false)) &&
+		!equalShapes(bootstrap.Shape(), reducedShape(shape, axis, true)) {
+		return nil, fmt.Errorf("lambdaReturns: expected bootstrap of shape "+
+			"%v but got %v", reducedShape(shape, axis, false),
+			bootstrap.Shape())
+	}
+
+	starts, stride := axisRows(shape, axis)
+	n := shape[axis]
+
+	switch rewards.Dtype() {
+	case tensor.Float64:
+		r, _ := float64Data(rewards)
+		v, _ := float64Data(values)
+		b, _ := float64Data(bootstrap)
+		out := make([]float64, len(r))
+		for row, start := range starts {
+			// The return and value of the state after step j
+			g, next := b[row], b[row]
+			for j := n - 1; j >= 0; j-- {
+				i := start + j*stride
+				if done[i] {
+					g, next = 0, 0
+				}
+				g = r[i] + gamma*((1-lambda)*next+lambda*g)
+				out[i] = g
+				next = v[i]
+			}
+		}
+		return newTensor(shape.Clone(), out), nil
+
+	default:
+		r, _ := float32Data(rewards)
+		v, _ := float32Data(values)
+		b, _ := float32Data(bootstrap)
+		out := make([]float32, len(r))
+		gamma, lambda := float32(gamma), float32(lambda)
+		for row, start := range starts {
+			g, next := b[row], b[row]
+			for j := n - 1; j >= 0; j-- {
+				i := start + j*stride
+				if done[i] {
+					g, next = 0, 0
+				}
+				g = r[i] + gamma*((1-lambda)*next+lambda*g)
+				out[i] = g
+				next = v[i]
+			}
+		}
+		return newTensor(shape.Clone(), out), nil
+	}
+}
+
+// checkReturnsArgs checks the arguments common to DiscountedReturns
+// and LambdaReturns, returning whether the episode ends at each
+// element of dones
+func checkReturnsArgs(rewards, dones tensor.Tensor, axis int) ([]bool,
+	error) {
+	if rewards.Dtype() != tensor.Float64 && rewards.Dtype() != tensor.Float32 {
+		return nil, fmt.Errorf("expected rewards of type %v or %v but got %v",
+			tensor.Float64, tensor.Float32, rewards.Dtype())
+	}
+	if axis < 0 || axis >= len(rewards.Shape()) {
+		return nil, fmt.Errorf("axis out of range [%v] for tensor with %v "+
+			"dimensions", axis, len(rewards.Shape()))
+	}
+	if !equalShapes(dones.Shape(), rewards.Shape()) {
+		return nil, fmt.Errorf("expected dones of shape %v but got %v",
+			rewards.Shape(), dones.Shape())
+	}
+
+	switch dones.Dtype() {
+	case tensor.Bool:
+		return boolData(dones)
+
+	case rewards.Dtype():
+		d, _ := toFloat64Data(dones)
+		done := make([]bool, len(d))
+		for i := range d {
+			done[i] = d[i] != 0
+		}
+		return done, nil
+
+	default:
+		return nil, fmt.Errorf("expected dones of type %v or %v but got %v",
+			tensor.Bool, rewards.Dtype(), dones.Dtype())
+	}
+}
@@ -0,0 +1,164 @@
+package top
+
+import (
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestDiscountedReturns(t *testing.T) {
+	// Rewards of shape (time, env), where the first environment ends
+	// an episode at step 1
+	rewards := tensor.NewDense(
+		tensor.Float64,
+		[]int{3, 2},
+		tensor.WithBacking([]float64{1, 1, 2, 2, 4, 4}),
+	)
+	dones := tensor.NewDense(
+		tensor.Float64,
+		[]int{3, 2},
+		tensor.WithBacking([]float64{0, 0, 1, 0, 0, 0}),
+	)
+
+	out, err := DiscountedReturns(rewards, dones, 0.5, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := tensor.NewDense(
+		tensor.Float64,
+		[]int{3, 2},
+		tensor.WithBacking([]float64{2, 3, 2, 4, 4, 4}),
+	)
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	// The same batch with shape (env, time), float32 rewards and bool
+	// dones
+	rewards = tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 3},
+		tensor.WithBacking([]float32{1, 2, 4, 1, 2, 4}),
+	)
+	boolDones := tensor.NewDense(
+		tensor.Bool,
+		[]int{2, 3},
+		tensor.WithBacking([]bool{false, true, false, false, false, false}),
+	)
+	out, err = DiscountedReturns(rewards, boolDones, 0.5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target = tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 3},
+		tensor.WithBacking([]float32{2, 2, 4, 3, 4, 4}),
+	)
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	// Errors
+	if _, err := DiscountedReturns(rewards, boolDones, 0.5, 2); err == nil {
+		t.Error("expected error for axis out of range")
+	}
+	if _, err := DiscountedReturns(rewards, dones, 0.5, 0); err == nil {
+		t.Error("expected error for dones of wrong type and shape")
+	}
+	ints := tensor.New(tensor.WithShape(2, 3),
+		tensor.WithBacking([]int{0, 0, 0, 0, 0, 0}))
+	if _, err := DiscountedReturns(ints, boolDones, 0.5, 0); err == nil {
+		t.Error("expected error for int rewards")
+	}
+}
+
+func TestLambdaReturns(t *testing.T) {
+	rewards := tensor.NewDense(
+		tensor.Float64,
+		[]int{3, 2},
+		tensor.WithBacking([]float64{1, 1, 2, 2, 4, 4}),
+	)
+	values := tensor.NewDense(
+		tensor.Float64,
+		[]int{3, 2},
+		tensor.WithBacking([]float64{10, 10, 20, 20, 40, 40}),
+	)
+	bootstrap := tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{8, 8}),
+	)
+	dones := tensor.NewDense(
+		tensor.Bool,
+		[]int{3, 2},
+		tensor.WithBacking([]bool{false, false, true, false, false, false}),
+	)
+
+	// With lambda = 0, the returns are the one-step TD targets
+	out, err := LambdaReturns(rewards, values, bootstrap, dones, 0.5, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := tensor.NewDense(
+		tensor.Float64,
+		[]int{3, 2},
+		tensor.WithBacking([]float64{
+			1 + 0.5*20, 1 + 0.5*20,
+			2, 2 + 0.5*40,
+			4 + 0.5*8, 4 + 0.5*8,
+		}),
+	)
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	// With lambda = 1, the returns are the discounted returns
+	// bootstrapped from the value after the last step
+	out, err = LambdaReturns(rewards, values, bootstrap, dones, 0.5, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target = tensor.NewDense(
+		tensor.Float64,
+		[]int{3, 2},
+		tensor.WithBacking([]float64{2, 4, 2, 6, 8, 8}),
+	)
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	// Intermediate lambda on a single float32 episode with a scalar
+	// bootstrap, where
+	//	G[2] = 4 + 0.5 * 8 = 8
+	//	G[1] = 2 + 0.5 * (0.5 * 40 + 0.5 * 8) = 14
+	//	G[0] = 1 + 0.5 * (0.5 * 20 + 0.5 * 14) = 9.5
+	out, err = LambdaReturns(
+		tensor.New(tensor.WithShape(3), tensor.WithBacking([]float32{1, 2, 4})),
+		tensor.New(tensor.WithShape(3),
+			tensor.WithBacking([]float32{10, 20, 40})),
+		tensor.New(tensor.FromScalar(float32(8))),
+		tensor.New(tensor.WithShape(3), tensor.WithBacking([]float32{0, 0, 0})),
+		0.5, 0.5, 0,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target = tensor.NewDense(
+		tensor.Float32,
+		[]int{3},
+		tensor.WithBacking([]float32{9.5, 14, 8}),
+	)
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	// Errors
+	if _, err := LambdaReturns(rewards, values, values, dones, 0.5, 0.5,
+		0); err == nil {
+		t.Error("expected error for bootstrap of wrong shape")
+	}
+	if _, err := LambdaReturns(rewards, bootstrap, bootstrap, dones, 0.5,
+		0.5, 0); err == nil {
+		t.Error("expected error for values of wrong shape")
+	}
+}
@@ -0,0 +1,114 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// ScatterAdd returns a tensor of the argument shape which is zero
+// everywhere except at the positions given by indices along axis,
+// where the elements of src are accumulated. For a 3D tensor, the
+// output is specified by:
+//
+//	out[index[i][j][k]][j][k] += src[i][j][k]  # if axis == 0
+//	out[i][index[i][j][k]][k] += src[i][j][k]  # if axis == 1
+//	out[i][j][index[i][j][k]] += src[i][j][k]  # if axis == 2
+//
+// This is the adjoint of Gather, so that ScatterAdd computes the
+// gradient of Gather with respect to its input tensor when src is the
+// gradient with respect to the output of Gather.
+//
+// The src tensor must store float64's, float32's, or any integer type,
+// and the indices tensor must have the same shape as src and store any
+// integer type. If src has an integer type, the returned tensor will
+// be of type tensor.Int.
+func ScatterAdd(src tensor.Tensor, axis int, indices tensor.Tensor,
+	shape tensor.Shape) (tensor.Tensor, error) {
+	if !src.Shape().Eq(indices.Shape()) {
+		return nil, fmt.Errorf("scatterAdd: src and indices must have the "+
+			"same shape but got src=%v and indices=%v", src.Shape(),
+			indices.Shape())
+	}
+	if len(shape) != len(indices.Shape()) {
+		return nil, fmt.Errorf("scatterAdd: indices must have %v dimensions "+
+			"but got %v", len(shape), len(indices.Shape()))
+	}
+	if axis < 0 || axis >= len(shape) {
+		return nil, fmt.Errorf("scatterAdd: axis out of range [%v] for "+
+			"tensor with %v dimensions", axis, len(shape))
+	}
+	for i := range shape {
+		if i != axis && indices.Shape()[i] > shape[i] {
+			return nil, fmt.Errorf("scatterAdd: size does not match at "+
+				"dimension %v expected indices shape %v to be smaller "+
+				"than shape %v apart from dimension %v", i, indices.Shape(),
+				shape, axis)
+		}
+	}
+
+	ind, err := intData(indices)
+	if err != nil {
+		return nil, fmt.Errorf("scatterAdd: %v", err)
+	}
+
+	// Compute the index into the output backing data at which each
+	// element of src will be accumulated
+	strides := shape.CalcStrides()
+	srcShape := indices.Shape()
+	targets := make([]int, len(ind))
+	coords := make([]int, len(srcShape))
+	for i := range ind {
+		if ind[i] < 0 || ind[i] >= shape[axis] {
+			return nil, fmt.Errorf("scatterAdd: index %v out of range for "+
+				"dimension %v with size %v", ind[i], axis, shape[axis])
+		}
+
+		for dim := range coords {
+			if dim == axis {
+				targets[i] += ind[i] * strides[dim]
+			} else {
+				targets[i] += coords[dim] * strides[dim]
+			}
+		}
+
+		// Increment the coordinates, carrying into earlier dimensions
+		for dim := len(coords) - 1; dim >= 0; dim-- {
+			coords[dim]++
+			if coords[dim] < srcShape[dim] {
+				break
+			}
+			coords[dim] = 0
+		}
+	}
+
+	switch {
+	case src.Dtype() == tensor.Float64:
+		data, _ := float64Data(src)
+		out := make([]float64, shape.TotalSize())
+		for i, j := range targets {
+			out[j] += data[i]
+		}
+		return newTensor(shape, out), nil
+
+	case src.Dtype() == tensor.Float32:
+		data, _ := float32Data(src)
+		out := make([]float32, shape.TotalSize())
+		for i, j := range targets {
+			out[j] += data[i]
+		}
+		return newTensor(shape, out), nil
+
+	case isIntDtype(src.Dtype()):
+		data, _ := intData(src)
+		out := make([]int, shape.TotalSize())
+		for i, j := range targets {
+			out[j] += data[i]
+		}
+		return newTensor(shape, out), nil
+
+	default:
+		return nil, fmt.Errorf("scatterAdd: cannot scatter tensor of type %v",
+			src.Dtype())
+	}
+}
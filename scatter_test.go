@@ -0,0 +1,52 @@
+package top
+
+import (
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestScatterAdd(t *testing.T) {
+	src := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6}),
+	)
+	indices := tensor.NewDense(
+		tensor.Int8,
+		[]int{2, 3},
+		tensor.WithBacking([]int8{0, 0, 3, 1, 1, 1}),
+	)
+	out := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 4},
+		tensor.WithBacking([]float64{3, 0, 0, 3, 0, 15, 0, 0}),
+	)
+
+	pred, err := ScatterAdd(src, 1, indices, []int{2, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pred.Eq(out) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", out, pred)
+	}
+
+	// Gathering the scattered tensor along the same indices recovers
+	// the sums of the elements of src sharing an index
+	gathered, err := Gather(pred, 1, indices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sums := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{3, 3, 3, 15, 15, 15}),
+	)
+	if !gathered.Eq(sums) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", sums, gathered)
+	}
+
+	if _, err := ScatterAdd(src, 1, indices, []int{2, 3}); err == nil {
+		t.Error("expected error for out of range index")
+	}
+}
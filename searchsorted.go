@@ -0,0 +1,181 @@
+package top
+
+import (
+	"fmt"
+	"sort"
+
+	"gorgonia.org/tensor"
+)
+
+// Side determines which index is returned by SearchSorted when a value
+// is equal to an element of the sorted sequence.
+type Side int
+
+const (
+	// SideLeft returns the index of the first suitable location
+	SideLeft Side = iota
+
+	// SideRight returns the index of the last suitable location
+	SideRight
+)
+
+// SearchSorted returns an int tensor of indices into the innermost
+// (last) dimension of sortedSeq such that inserting each element of
+// values before its index would preserve the order of sortedSeq along
+// its last dimension. If side is SideLeft, the returned index i of
+// value v satisfies:
+//
+//	sortedSeq[..., i-1] < v <= sortedSeq[..., i]
+//
+// and if side is SideRight, the returned index i satisfies:
+//
+//	sortedSeq[..., i-1] <= v < sortedSeq[..., i]
+//
+// If sortedSeq is a 1D tensor, every element of values is searched
+// for in sortedSeq. Otherwise, sortedSeq and values must have the same
+// shape apart from their last dimensions, and each row of values along
+// its last dimension is searched for in the corresponding row of
+// sortedSeq. The returned tensor has the same shape as values.
+//
+// If sorter is nil, sortedSeq must be sorted in ascending order along
+// its last dimension. Otherwise, sorter must be an integer tensor of
+// the same shape as sortedSeq holding the indices that would sort
+// sortedSeq along its last dimension, such as those returned by
+// Argsort, and sortedSeq does not need to be sorted. In this case,
+// the returned indices are indices into the sorted sequence.
+//
+// SearchSorted works on sortedSeq and values of type float64 or
+// float32, or of any int type. Either both tensors must have a
+// floating point type or both must have an integer type.
+//
+// This implementation is heavily based on the PyTorch implementation.
+// See PyTorch's documentation for more details and usage:
+// https://pytorch.org/docs/stable/generated/torch.searchsorted.html
+func SearchSorted(sortedSeq, values tensor.Tensor, side Side,
+	sorter tensor.Tensor) (tensor.Tensor, error) {
+	seqShape, valShape := sortedSeq.Shape(), values.Shape()
+	if len(seqShape) == 0 {
+		return nil, fmt.Errorf("searchSorted: sortedSeq cannot be a scalar")
+	}
+
+	// Ensure leading dimensions match for batched sequences
+	if len(seqShape) > 1 {
+		if len(seqShape) != len(valShape) ||
+			!seqShape[:len(seqShape)-1].Eq(valShape[:len(valShape)-1]) {
+			return nil, fmt.Errorf("searchSorted: sortedSeq and values "+
+				"must have the same shape apart from their last "+
+				"dimensions but got sortedSeq=%v and values=%v", seqShape,
+				valShape)
+		}
+	}
+
+	// Compute the order in which to visit each row of sortedSeq
+	seqLen := seqShape[len(seqShape)-1]
+	order := make([]int, seqShape.TotalSize())
+	if sorter != nil {
+		if !sorter.Shape().Eq(seqShape) {
+			return nil, fmt.Errorf("searchSorted: sorter must have shape %v "+
+				"but got %v", seqShape, sorter.Shape())
+		}
+
+		s, err := intData(sorter)
+		if err != nil {
+			return nil, fmt.Errorf("searchSorted: sorter: %v", err)
+		}
+		for i := range s {
+			if s[i] < 0 || s[i] >= seqLen {
+				return nil, fmt.Errorf("searchSorted: sorter index %v out "+
+					"of range for sequences of length %v", s[i], seqLen)
+			}
+			order[i] = (i/seqLen)*seqLen + s[i]
+		}
+	} else {
+		for i := range order {
+			order[i] = i
+		}
+	}
+
+	// less(i, j) reports whether element i of sortedSeq is less than
+	// element j of values, and greater(i, j) reports whether element i
+	// of sortedSeq is greater than element j of values
+	var less, greater func(i, j int) bool
+	switch {
+	case isIntDtype(sortedSeq.Dtype()) && isIntDtype(values.Dtype()):
+		seq, _ := intData(sortedSeq)
+		val, _ := intData(values)
+		less = func(i, j int) bool { return seq[i] < val[j] }
+		greater = func(i, j int) bool { return seq[i] > val[j] }
+
+	default:
+		seq, err := toFloat64Data(sortedSeq)
+		if err != nil {
+			return nil, fmt.Errorf("searchSorted: sortedSeq: %v", err)
+		}
+		val, err := toFloat64Data(values)
+		if err != nil {
+			return nil, fmt.Errorf("searchSorted: values: %v", err)
+		}
+		less = func(i, j int) bool { return seq[i] < val[j] }
+		greater = func(i, j int) bool { return seq[i] > val[j] }
+	}
+
+	valLen := 1
+	if len(valShape) > 0 {
+		valLen = valShape[len(valShape)-1]
+	}
+	indices := make([]int, valShape.TotalSize())
+	for j := range indices {
+		// Offset of the row of sortedSeq to search
+		row := 0
+		if len(seqShape) > 1 {
+			row = (j / valLen) * seqLen
+		}
+
+		if side == SideLeft {
+			indices[j] = sort.Search(seqLen, func(i int) bool {
+				return !less(order[row+i], j)
+			})
+		} else {
+			indices[j] = sort.Search(seqLen, func(i int) bool {
+				return greater(order[row+i], j)
+			})
+		}
+	}
+
+	return newTensor(valShape.Clone(), indices), nil
+}
+
+// Bucketize returns an int tensor holding the index of the bucket to
+// which each element of t belongs, where the buckets are defined by
+// the 1D tensor of boundaries, which must be sorted in ascending order.
+// If right is false, the returned index i of element x satisfies:
+//
+//	boundaries[i-1] < x <= boundaries[i]
+//
+// and if right is true, the returned index i satisfies:
+//
+//	boundaries[i-1] <= x < boundaries[i]
+//
+// The returned tensor has the same shape as t. See SearchSorted for
+// the supported data types.
+//
+// See PyTorch's documentation for more details and usage:
+// https://pytorch.org/docs/stable/generated/torch.bucketize.html
+func Bucketize(t, boundaries tensor.Tensor, right bool) (tensor.Tensor,
+	error) {
+	if len(boundaries.Shape()) != 1 {
+		return nil, fmt.Errorf("bucketize: boundaries must be a 1D tensor "+
+			"but got shape %v", boundaries.Shape())
+	}
+
+	side := SideLeft
+	if right {
+		side = SideRight
+	}
+
+	out, err := SearchSorted(boundaries, t, side, nil)
+	if err != nil {
+		return nil, fmt.Errorf("bucketize: %v", err)
+	}
+	return out, nil
+}
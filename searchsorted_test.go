@@ -0,0 +1,126 @@
+package top
+
+import (
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestSearchSorted(t *testing.T) {
+	seq := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 5},
+		tensor.WithBacking([]float64{1, 3, 5, 7, 9, 2, 4, 6, 8, 10}),
+	)
+	values := tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 3},
+		tensor.WithBacking([]float32{3, 6, 9, 1, 6, 11}),
+	)
+
+	left := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 3},
+		tensor.WithBacking([]int{1, 3, 4, 0, 2, 5}),
+	)
+	right := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 3},
+		tensor.WithBacking([]int{2, 3, 5, 0, 3, 5}),
+	)
+
+	out, err := SearchSorted(seq, values, SideLeft, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(left) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", left, out)
+	}
+
+	out, err = SearchSorted(seq, values, SideRight, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(right) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", right, out)
+	}
+
+	// Mismatched leading dimensions are errors
+	bad := tensor.NewDense(tensor.Float64, []int{3, 3})
+	if _, err := SearchSorted(seq, bad, SideLeft, nil); err == nil {
+		t.Error("expected error for illegal shapes")
+	}
+}
+
+// TestSearchSortedSorter tests SearchSorted on unsorted sequences
+// using the indices returned by Argsort as the sorter
+func TestSearchSortedSorter(t *testing.T) {
+	seq := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 4},
+		tensor.WithBacking([]int{8, 2, 6, 4, 1, 0, 3, 2}),
+	)
+	values := tensor.NewDense(
+		tensor.Int16,
+		[]int{2, 2},
+		tensor.WithBacking([]int16{5, 2, 2, 9}),
+	)
+	target := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 2},
+		tensor.WithBacking([]int{2, 0, 2, 4}),
+	)
+
+	sorter, err := Argsort(seq, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := SearchSorted(seq, values, SideLeft, sorter)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+}
+
+func TestBucketize(t *testing.T) {
+	boundaries := tensor.NewDense(
+		tensor.Float64,
+		[]int{3},
+		tensor.WithBacking([]float64{0, 0.5, 1}),
+	)
+	in := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{-1, 0, 0.25, 0.5, 0.75, 2}),
+	)
+
+	left := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 3},
+		tensor.WithBacking([]int{0, 0, 1, 1, 2, 3}),
+	)
+	right := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 3},
+		tensor.WithBacking([]int{0, 1, 1, 2, 2, 3}),
+	)
+
+	out, err := Bucketize(in, boundaries, false)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(left) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", left, out)
+	}
+
+	out, err = Bucketize(in, boundaries, true)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(right) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", right, out)
+	}
+}
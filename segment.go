@@ -0,0 +1,475 @@
+package top
+
+import (
+	"fmt"
+	"math"
+
+	"gorgonia.org/tensor"
+)
+
+// segmentOp is a reduction computed over segments
+type segmentOp int
+
+const (
+	segmentSum segmentOp = iota
+	segmentMean
+	segmentMax
+	segmentMin
+)
+
+// SegmentSum computes the sum along segments of data. The segmentIDs
+// tensor is a 1D tensor of non-negative integers, sorted in
+// non-decreasing order, with one element for each element of data
+// along its first dimension. Element i of segmentIDs holds the
+// segment to which slice i of data along the first dimension belongs.
+// The returned tensor has the same shape as data, except that the
+// first dimension has size one more than the largest segment ID, and
+// it is specified by:
+//
+//	out[s][...] = sum over i with segmentIDs[i] == s of data[i][...]
+//
+// Segments which do not appear in segmentIDs are 0.
+//
+// The segment reductions work on data of type float64, float32, or any
+// int type, and segmentIDs of any int type. If data has an integer
+// type (e.g. uint32), the returned tensor will be of type tensor.Int.
+//
+// This implementation follows the TensorFlow implementation. See
+// TensorFlow's documentation for more details and usage:
+// https://www.tensorflow.org/api_docs/python/tf/math/segment_sum
+func SegmentSum(data, segmentIDs tensor.Tensor) (tensor.Tensor, error) {
+	return sortedSegmentReduce(data, segmentIDs, segmentSum, "segmentSum")
+}
+
+// SegmentMean computes the mean along segments of data. If data has an
+// integer type, the mean is computed using integer division. See
+// SegmentSum for more details.
+func SegmentMean(data, segmentIDs tensor.Tensor) (tensor.Tensor, error) {
+	return sortedSegmentReduce(data, segmentIDs, segmentMean, "segmentMean")
+}
+
+// SegmentMax computes the maximum along segments of data. See SegmentSum
+// for more details.
+func SegmentMax(data, segmentIDs tensor.Tensor) (tensor.Tensor, error) {
+	return sortedSegmentReduce(data, segmentIDs, segmentMax, "segmentMax")
+}
+
+// SegmentMin computes the minimum along segments of data. See SegmentSum
+// for more details.
+func SegmentMin(data, segmentIDs tensor.Tensor) (tensor.Tensor, error) {
+	return sortedSegmentReduce(data, segmentIDs, segmentMin, "segmentMin")
+}
+
+// UnsortedSegmentSum computes the sum along segments of data, where the
+// segment IDs need not be sorted. The returned tensor has the same
+// shape as data, except that the first dimension has size numSegments.
+// Segment IDs must be smaller than numSegments, and negative segment
+// IDs are allowed, in which case the corresponding slices of data are
+// dropped. Segments which do not appear in segmentIDs are 0. See
+// SegmentSum for more details.
+func UnsortedSegmentSum(data, segmentIDs tensor.Tensor,
+	numSegments int) (tensor.Tensor, error) {
+	return unsortedSegmentReduce(data, segmentIDs, numSegments, segmentSum,
+		"unsortedSegmentSum")
+}
+
+// UnsortedSegmentMean computes the mean along segments of data, where
+// the segment IDs need not be sorted. Segments which do not appear in
+// segmentIDs are 0. See UnsortedSegmentSum for more details.
+func UnsortedSegmentMean(data, segmentIDs tensor.Tensor,
+	numSegments int) (tensor.Tensor, error) {
+	return unsortedSegmentReduce(data, segmentIDs, numSegments, segmentMean,
+		"unsortedSegmentMean")
+}
+
+// UnsortedSegmentMax computes the maximum along segments of data, where
+// the segment IDs need not be sorted. Segments which do not appear in
+// segmentIDs hold the lowest value of the returned type (-Inf for
+// floating point types). See UnsortedSegmentSum for more details.
+func UnsortedSegmentMax(data, segmentIDs tensor.Tensor,
+	numSegments int) (tensor.Tensor, error) {
+	return unsortedSegmentReduce(data, segmentIDs, numSegments, segmentMax,
+		"unsortedSegmentMax")
+}
+
+// UnsortedSegmentMin computes the minimum along segments of data, where
+// the segment IDs need not be sorted. Segments which do not appear in
+// segmentIDs hold the largest value of the returned type (+Inf for
+// floating point types). See UnsortedSegmentSum for more details.
+func UnsortedSegmentMin(data, segmentIDs tensor.Tensor,
+	numSegments int) (tensor.Tensor, error) {
+	return unsortedSegmentReduce(data, segmentIDs, numSegments, segmentMin,
+		"unsortedSegmentMin")
+}
+
+// sortedSegmentReduce reduces data along sorted segments
+func sortedSegmentReduce(data, segmentIDs tensor.Tensor, op segmentOp,
+	name string) (tensor.Tensor, error) {
+	ids, err := segmentIDData(data, segmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", name, err)
+	}
+
+	numSegments := 0
+	for i := range ids {
+		if ids[i] < 0 || (i > 0 && ids[i] < ids[i-1]) {
+			return nil, fmt.Errorf("%v: segment IDs must be non-negative "+
+				"and sorted but got %v", name, ids)
+		}
+		numSegments = ids[i] + 1
+	}
+
+	out, err := segmentReduce(data, ids, numSegments, op, true)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", name, err)
+	}
+	return out, nil
+}
+
+// unsortedSegmentReduce reduces data along unsorted segments
+func unsortedSegmentReduce(data, segmentIDs tensor.Tensor, numSegments int,
+	op segmentOp, name string) (tensor.Tensor, error) {
+	ids, err := segmentIDData(data, segmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", name, err)
+	}
+
+	if numSegments < 1 {
+		return nil, fmt.Errorf("%v: numSegments must be positive "+
+			"but got %v", name, numSegments)
+	}
+	for _, id := range ids {
+		if id >= numSegments {
+			return nil, fmt.Errorf("%v: segment ID %v out of range "+
+				"for %v segments", name, id, numSegments)
+		}
+	}
+
+	out, err := segmentReduce(data, ids, numSegments, op, false)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", name, err)
+	}
+	return out, nil
+}
+
+// segmentIDData checks that segmentIDs is a legal 1D tensor of segment
+// IDs for data and returns its elements
+func segmentIDData(data, segmentIDs tensor.Tensor) ([]int, error) {
+	if len(data.Shape()) == 0 {
+		return nil, fmt.Errorf("data cannot be a scalar")
+	}
+	if len(segmentIDs.Shape()) != 1 ||
+		segmentIDs.Shape()[0] != data.Shape()[0] {
+		return nil, fmt.Errorf("segmentIDs must be a 1D tensor of shape "+
+			"(%v) but got %v", data.Shape()[0], segmentIDs.Shape())
+	}
+
+	ids, err := intData(segmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("segmentIDs: %v", err)
+	}
+	return ids, nil
+}
+
+// segmentReduce reduces data along segments with the argument segment
+// IDs, of which there are numSegments. Negative segment IDs are
+// skipped. If emptyZero is true, empty segments are 0, otherwise they
+// are the identity of op.
+func segmentReduce(data tensor.Tensor, ids []int, numSegments int,
+	op segmentOp, emptyZero bool) (tensor.Tensor, error) {
+	shape := data.Shape().Clone()
+	shape[0] = numSegments
+	inner := tensor.ProdInts([]int(data.Shape()[1:]))
+
+	counts := make([]int, numSegments)
+	for _, id := range ids {
+		if id >= 0 {
+			counts[id]++
+		}
+	}
+
+	switch {
+	case data.Dtype() == tensor.Float64 || data.Dtype() == tensor.Float32:
+		d, _ := toFloat64Data(data)
+		out := segmentReduceF64(d, ids, counts, inner, op, emptyZero)
+		return fromFloat64Data(data.Dtype(), shape, out), nil
+
+	case isIntDtype(data.Dtype()):
+		d, _ := intData(data)
+		out := segmentReduceInt(d, ids, counts, inner, op, emptyZero)
+		return newTensor(shape, out), nil
+
+	default:
+		return nil, fmt.Errorf("cannot reduce tensor of type %v",
+			data.Dtype())
+	}
+}
+
+// segmentReduceF64 reduces the float64 data along segments. Each
+// segment consists of inner elements. See segmentReduce.
+func segmentReduceF64(data []float64, ids, counts []int, inner int,
+	op segmentOp, emptyZero bool) []float64 {
+	out := make([]float64, len(counts)*inner)
+	switch op {
+	case segmentMax:
+		for i := range out {
+			out[i] = math.Inf(-1)
+		}
+	case segmentMin:
+		for i := range out {
+			out[i] = math.Inf(1)
+		}
+	}
+
+	for i, id := range ids {
+		if id < 0 {
+			continue
+		}
+		for k := 0; k < inner; k++ {
+			j := id*inner + k
+			value := data[i*inner+k]
+			switch op {
+			case segmentSum, segmentMean:
+				out[j] += value
+			case segmentMax:
+				out[j] = math.Max(out[j], value)
+			case segmentMin:
+				out[j] = math.Min(out[j], value)
+			}
+		}
+	}
+
+	for s, count := range counts {
+		for k := 0; k < inner; k++ {
+			j := s*inner + k
+			if count == 0 && emptyZero {
+				out[j] = 0
+			} else if count != 0 && op == segmentMean {
+				out[j] /= float64(count)
+			}
+		}
+	}
+	return out
+}
+
+// segmentReduceInt reduces the int data along segments. Each segment
+// consists of inner elements. See segmentReduce.
+func segmentReduceInt(data []int, ids, counts []int, inner int, op segmentOp,
+	emptyZero bool) []int {
+	out := make([]int, len(counts)*inner)
+	switch op {
+	case segmentMax:
+		for i := range out {
+			out[i] = math.MinInt
+		}
+	case segmentMin:
+		for i := range out {
+			out[i] = math.MaxInt
+		}
+	}
+
+	for i, id := range ids {
+		if id < 0 {
+			continue
+		}
+		for k := 0; k < inner; k++ {
+			j := id*inner + k
+			value := data[i*inner+k]
+			switch {
+			case op == segmentSum || op == segmentMean:
+				out[j] += value
+			case op == segmentMax && value > out[j]:
+				out[j] = value
+			case op == segmentMin && value < out[j]:
+				out[j] = value
+			}
+		}
+	}
+
+	for s, count := range counts {
+		for k := 0; k < inner; k++ {
+			j := s*inner + k
+			if count == 0 && emptyZero {
+				out[j] = 0
+			} else if count != 0 && op == segmentMean {
+				out[j] /= count
+			}
+		}
+	}
+	return out
+}
+
+// SegmentSumB is the backward pass of SegmentSum and UnsortedSegmentSum.
+// Given the gradient grad with respect to the output of SegmentSum and
+// the segment IDs used in the forward pass, SegmentSumB returns the
+// gradient with respect to the data argument of SegmentSum. Slices
+// of data with negative segment IDs have a gradient of 0.
+//
+// The backward passes of the segment reductions work on grad of type
+// float64 or float32.
+func SegmentSumB(grad, segmentIDs tensor.Tensor) (tensor.Tensor, error) {
+	out, err := segmentSumMeanB(grad, segmentIDs, false)
+	if err != nil {
+		return nil, fmt.Errorf("segmentSumB: %v", err)
+	}
+	return out, nil
+}
+
+// SegmentMeanB is the backward pass of SegmentMean and
+// UnsortedSegmentMean. See SegmentSumB for more details.
+func SegmentMeanB(grad, segmentIDs tensor.Tensor) (tensor.Tensor, error) {
+	out, err := segmentSumMeanB(grad, segmentIDs, true)
+	if err != nil {
+		return nil, fmt.Errorf("segmentMeanB: %v", err)
+	}
+	return out, nil
+}
+
+// segmentSumMeanB computes the backward pass of the segment sum, or of
+// the segment mean if mean is true
+func segmentSumMeanB(grad, segmentIDs tensor.Tensor,
+	mean bool) (tensor.Tensor, error) {
+	if len(grad.Shape()) == 0 || len(segmentIDs.Shape()) != 1 {
+		return nil, fmt.Errorf("expected non-scalar grad and 1D segmentIDs "+
+			"but got grad=%v and segmentIDs=%v", grad.Shape(),
+			segmentIDs.Shape())
+	}
+
+	ids, err := intData(segmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("segmentIDs: %v", err)
+	}
+	g, err := toFloat64Data(grad)
+	if err != nil {
+		return nil, fmt.Errorf("grad: %v", err)
+	}
+
+	numSegments := grad.Shape()[0]
+	counts := make([]int, numSegments)
+	for _, id := range ids {
+		if id >= numSegments {
+			return nil, fmt.Errorf("segment ID %v out of range for %v "+
+				"segments", id, numSegments)
+		}
+		if id >= 0 {
+			counts[id]++
+		}
+	}
+
+	shape := grad.Shape().Clone()
+	shape[0] = len(ids)
+	inner := tensor.ProdInts([]int(shape[1:]))
+	out := make([]float64, shape.TotalSize())
+	for i, id := range ids {
+		if id < 0 {
+			continue
+		}
+		scale := 1.0
+		if mean {
+			scale = float64(counts[id])
+		}
+		for k := 0; k < inner; k++ {
+			out[i*inner+k] = g[id*inner+k] / scale
+		}
+	}
+
+	return fromFloat64Data(grad.Dtype(), shape, out), nil
+}
+
+// SegmentMaxB is the backward pass of SegmentMax and UnsortedSegmentMax.
+// Given the gradient grad with respect to the output of SegmentMax,
+// the data and segment IDs used in the forward pass, and the output
+// of the forward pass, SegmentMaxB returns the gradient with respect to
+// the data argument of SegmentMax. The gradient is routed to the
+// maximal elements of each segment. If multiple elements of a segment
+// tie for the maximum, the gradient is split evenly between them.
+//
+// See SegmentSumB for more details.
+func SegmentMaxB(grad, data, segmentIDs, output tensor.Tensor) (tensor.Tensor,
+	error) {
+	out, err := segmentExtremumB(grad, data, segmentIDs, output)
+	if err != nil {
+		return nil, fmt.Errorf("segmentMaxB: %v", err)
+	}
+	return out, nil
+}
+
+// SegmentMinB is the backward pass of SegmentMin and UnsortedSegmentMin.
+// See SegmentMaxB for more details.
+func SegmentMinB(grad, data, segmentIDs, output tensor.Tensor) (tensor.Tensor,
+	error) {
+	out, err := segmentExtremumB(grad, data, segmentIDs, output)
+	if err != nil {
+		return nil, fmt.Errorf("segmentMinB: %v", err)
+	}
+	return out, nil
+}
+
+// segmentExtremumB computes the backward pass of the segment maximum or
+// minimum, routing the gradient to the elements of data equal to the
+// output of their segment
+func segmentExtremumB(grad, data, segmentIDs,
+	output tensor.Tensor) (tensor.Tensor, error) {
+	ids, err := segmentIDData(data, segmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	if !grad.Shape().Eq(output.Shape()) {
+		return nil, fmt.Errorf("grad and output must have the same shape "+
+			"but got grad=%v and output=%v", grad.Shape(), output.Shape())
+	}
+	if len(grad.Shape()) != len(data.Shape()) ||
+		!grad.Shape()[1:].Eq(data.Shape()[1:]) {
+		return nil, fmt.Errorf("grad must have the same shape as data "+
+			"apart from the first dimension but got grad=%v and data=%v",
+			grad.Shape(), data.Shape())
+	}
+
+	g, err := toFloat64Data(grad)
+	if err != nil {
+		return nil, fmt.Errorf("grad: %v", err)
+	}
+	d, err := numericFloat64Data(data)
+	if err != nil {
+		return nil, fmt.Errorf("data: %v", err)
+	}
+	o, err := numericFloat64Data(output)
+	if err != nil {
+		return nil, fmt.Errorf("output: %v", err)
+	}
+
+	numSegments := grad.Shape()[0]
+	inner := tensor.ProdInts([]int(data.Shape()[1:]))
+
+	// Count the number of elements which tie for each extremum
+	ties := make([]int, len(o))
+	for i, id := range ids {
+		if id >= numSegments {
+			return nil, fmt.Errorf("segment ID %v out of range for %v "+
+				"segments", id, numSegments)
+		}
+		if id < 0 {
+			continue
+		}
+		for k := 0; k < inner; k++ {
+			if d[i*inner+k] == o[id*inner+k] {
+				ties[id*inner+k]++
+			}
+		}
+	}
+
+	out := make([]float64, len(d))
+	for i, id := range ids {
+		if id < 0 {
+			continue
+		}
+		for k := 0; k < inner; k++ {
+			j := id*inner + k
+			if d[i*inner+k] == o[j] {
+				out[i*inner+k] = g[j] / float64(ties[j])
+			}
+		}
+	}
+
+	return fromFloat64Data(grad.Dtype(), data.Shape().Clone(), out), nil
+}
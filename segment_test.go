@@ -0,0 +1,211 @@
+package top
+
+import (
+	"math"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestSegmentReductions(t *testing.T) {
+	data := tensor.NewDense(
+		tensor.Float64,
+		[]int{5, 2},
+		tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, -1}),
+	)
+	ids := tensor.NewDense(
+		tensor.Int32,
+		[]int{5},
+		tensor.WithBacking([]int32{0, 0, 2, 2, 2}),
+	)
+
+	ops := []func(tensor.Tensor, tensor.Tensor) (tensor.Tensor, error){
+		SegmentSum, SegmentMean, SegmentMax, SegmentMin,
+	}
+	outBacking := [][]float64{
+		{4, 6, 0, 0, 21, 13},
+		{2, 3, 0, 0, 7, 13.0 / 3},
+		{3, 4, 0, 0, 9, 8},
+		{1, 2, 0, 0, 5, -1},
+	}
+
+	for i := range ops {
+		out := tensor.NewDense(
+			tensor.Float64,
+			[]int{3, 2},
+			tensor.WithBacking(outBacking[i]),
+		)
+
+		pred, err := ops[i](data, ids)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !pred.Eq(out) {
+			t.Errorf("%d: expected: \n%v \nreceived: \n%v", i, out, pred)
+		}
+	}
+
+	if _, err := SegmentSum(data, tensor.NewDense(
+		tensor.Int,
+		[]int{5},
+		tensor.WithBacking([]int{2, 0, 0, 1, 1}),
+	)); err == nil {
+		t.Error("expected error for unsorted segment IDs")
+	}
+	if _, err := SegmentSum(data, tensor.NewDense(
+		tensor.Int,
+		[]int{4},
+		tensor.WithBacking([]int{0, 0, 1, 1}),
+	)); err == nil {
+		t.Error("expected error for segment IDs of the wrong shape")
+	}
+}
+
+func TestUnsortedSegmentReductions(t *testing.T) {
+	data := tensor.NewDense(
+		tensor.Float32,
+		[]int{5},
+		tensor.WithBacking([]float32{1, 2, 3, 4, 5}),
+	)
+	ids := tensor.NewDense(
+		tensor.Uint8,
+		[]int{5},
+		tensor.WithBacking([]uint8{2, 0, 2, 0, 0}),
+	)
+
+	ops := []func(tensor.Tensor, tensor.Tensor, int) (tensor.Tensor, error){
+		UnsortedSegmentSum, UnsortedSegmentMean, UnsortedSegmentMax,
+		UnsortedSegmentMin,
+	}
+	inf := float32(math.Inf(1))
+	outBacking := [][]float32{
+		{11, 0, 4},
+		{11.0 / 3, 0, 2},
+		{5, -inf, 3},
+		{2, inf, 1},
+	}
+
+	for i := range ops {
+		out := tensor.NewDense(
+			tensor.Float32,
+			[]int{3},
+			tensor.WithBacking(outBacking[i]),
+		)
+
+		pred, err := ops[i](data, ids, 3)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !pred.Eq(out) {
+			t.Errorf("%d: expected: \n%v \nreceived: \n%v", i, out, pred)
+		}
+	}
+
+	// Negative segment IDs are dropped
+	intData := tensor.NewDense(
+		tensor.Int16,
+		[]int{4},
+		tensor.WithBacking([]int16{1, 2, 3, 4}),
+	)
+	negIDs := tensor.NewDense(
+		tensor.Int,
+		[]int{4},
+		tensor.WithBacking([]int{1, -1, 1, 0}),
+	)
+	out := tensor.NewDense(
+		tensor.Int,
+		[]int{2},
+		tensor.WithBacking([]int{4, 4}),
+	)
+	pred, err := UnsortedSegmentSum(intData, negIDs, 2)
+	if err != nil {
+		t.Error(err)
+	} else if !pred.Eq(out) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", out, pred)
+	}
+
+	if _, err := UnsortedSegmentSum(intData, negIDs, 1); err == nil {
+		t.Error("expected error for out of range segment ID")
+	}
+}
+
+func TestSegmentB(t *testing.T) {
+	data := tensor.NewDense(
+		tensor.Float64,
+		[]int{5},
+		tensor.WithBacking([]float64{1, 3, 3, 4, 2}),
+	)
+	ids := tensor.NewDense(
+		tensor.Int,
+		[]int{5},
+		tensor.WithBacking([]int{1, 1, 1, -1, 0}),
+	)
+	grad := tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{2, 6}),
+	)
+
+	sumB, err := SegmentSumB(grad, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sumOut := tensor.NewDense(
+		tensor.Float64,
+		[]int{5},
+		tensor.WithBacking([]float64{6, 6, 6, 0, 2}),
+	)
+	if !sumB.Eq(sumOut) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", sumOut, sumB)
+	}
+
+	meanB, err := SegmentMeanB(grad, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meanOut := tensor.NewDense(
+		tensor.Float64,
+		[]int{5},
+		tensor.WithBacking([]float64{2, 2, 2, 0, 2}),
+	)
+	if !meanB.Eq(meanOut) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", meanOut, meanB)
+	}
+
+	// The maximum of segment 1 is tied, so the gradient is split
+	max, err := UnsortedSegmentMax(data, ids, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxB, err := SegmentMaxB(grad, data, ids, max)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxOut := tensor.NewDense(
+		tensor.Float64,
+		[]int{5},
+		tensor.WithBacking([]float64{0, 3, 3, 0, 2}),
+	)
+	if !maxB.Eq(maxOut) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", maxOut, maxB)
+	}
+
+	min, err := UnsortedSegmentMin(data, ids, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minB, err := SegmentMinB(grad, data, ids, min)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minOut := tensor.NewDense(
+		tensor.Float64,
+		[]int{5},
+		tensor.WithBacking([]float64{6, 0, 0, 0, 2}),
+	)
+	if !minB.Eq(minOut) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", minOut, minB)
+	}
+}
@@ -0,0 +1,115 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// SegmentArgsort returns an int tensor containing indices that would
+// sort each segment of the 1D values tensor independently. The
+// segments are given by the 1D integer tensor segmentOffsets, which
+// holds the index of the first element of each segment in
+// non-decreasing order and must begin with 0. Segment i consists of
+// the elements in [segmentOffsets[i], segmentOffsets[i+1]), and the
+// last segment extends to the end of values. A final offset equal to
+// the length of values is therefore allowed and denotes an empty
+// segment, so that offsets in the compressed format
+// [0, len1, len1+len2, ..., len(values)] may be used as well.
+//
+// The returned indices are indices into values rather than into each
+// segment, so that the indices for segment i lie in
+// [segmentOffsets[i], segmentOffsets[i+1]) and gathering values along
+// axis 0 at the returned indices sorts each segment. The sort is
+// stable, and each segment is sorted concurrently.
+//
+// SegmentArgsort works on values of any type supported by Argsort, and
+// segmentOffsets of any integer type.
+func SegmentArgsort(values, segmentOffsets tensor.Tensor) (tensor.Tensor,
+	error) {
+	if len(values.Shape()) != 1 {
+		return nil, fmt.Errorf("segmentArgsort: values must be a 1D tensor "+
+			"but got shape %v", values.Shape())
+	}
+	if len(segmentOffsets.Shape()) != 1 {
+		return nil, fmt.Errorf("segmentArgsort: segmentOffsets must be a 1D "+
+			"tensor but got shape %v", segmentOffsets.Shape())
+	}
+
+	segments, err := intData(segmentOffsets)
+	if err != nil {
+		return nil, fmt.Errorf("segmentArgsort: segmentOffsets: %v", err)
+	}
+
+	size := values.Shape()[0]
+	if len(segments) == 0 || segments[0] != 0 {
+		return nil, fmt.Errorf("segmentArgsort: first segment offset must "+
+			"be 0 but got %v", segments)
+	}
+	for i := 1; i < len(segments); i++ {
+		if segments[i] < segments[i-1] || segments[i] > size {
+			return nil, fmt.Errorf("segmentArgsort: segment offsets must be "+
+				"non-decreasing and no larger than %v but got %v", size,
+				segments)
+		}
+	}
+
+	data := backing(values)
+	if sortableRow(data, nil) == nil {
+		return nil, fmt.Errorf("segmentArgsort: unknown tensor type %v",
+			values.Dtype())
+	}
+	indices := offsets(values)
+
+	// sortedInd[i] is the channel along which the argsort'd indices of
+	// segment i are sent. Each segment is sorted concurrently.
+	sortedInd := make([]chan []int, len(segments))
+	for i := range segments {
+		start := segments[i]
+		end := size
+		if i+1 < len(segments) {
+			end = segments[i+1]
+		}
+
+		sortedInd[i] = make(chan []int, 1)
+		go func(i int) {
+			sortedInd[i] <- argSort(sortableRow(data, indices[start:end]))
+		}(i)
+	}
+
+	// Set each segment based on the concurrent argsorts, offsetting the
+	// sorted indices within each segment so that they index values
+	sorted := make([]int, size)
+	for i, start := range segments {
+		for j, arg := range <-sortedInd[i] {
+			sorted[start+j] = start + arg
+		}
+	}
+
+	return tensor.NewDense(
+		tensor.Int,
+		[]int{size},
+		tensor.WithBacking(sorted),
+	), nil
+}
+
+// SegmentSort sorts each segment of the 1D values tensor independently,
+// where segments are given by segmentOffsets. See SegmentArgsort for
+// details on segmentOffsets.
+//
+// SegmentSort works on values of type float64, float32, or any int
+// type. If values has an integer type (e.g. uint32), the returned
+// tensor will be of type tensor.Int.
+func SegmentSort(values, segmentOffsets tensor.Tensor) (tensor.Tensor,
+	error) {
+	indices, err := SegmentArgsort(values, segmentOffsets)
+	if err != nil {
+		return nil, fmt.Errorf("segmentSort: %v", err)
+	}
+
+	sorted, err := Gather(values, 0, indices)
+	if err != nil {
+		return nil, fmt.Errorf("segmentSort: %v", err)
+	}
+	return sorted, nil
+}
@@ -0,0 +1,65 @@
+package top
+
+import (
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestSegmentArgsort(t *testing.T) {
+	values := tensor.NewDense(
+		tensor.Float64,
+		[]int{8},
+		tensor.WithBacking([]float64{3, 1, 2, 9, 4, 4, 0, 7}),
+	)
+
+	// Segments of lengths 3, 1, and 4 with and without a final offset
+	// equal to the length of values
+	offsets := []*tensor.Dense{
+		tensor.NewDense(tensor.Int, []int{3},
+			tensor.WithBacking([]int{0, 3, 4})),
+		tensor.NewDense(tensor.Uint8, []int{4},
+			tensor.WithBacking([]uint8{0, 3, 4, 8})),
+	}
+	target := tensor.NewDense(
+		tensor.Int,
+		[]int{8},
+		tensor.WithBacking([]int{1, 2, 0, 3, 6, 4, 5, 7}),
+	)
+	sortedTarget := tensor.NewDense(
+		tensor.Float64,
+		[]int{8},
+		tensor.WithBacking([]float64{1, 2, 3, 9, 0, 4, 4, 7}),
+	)
+
+	for _, offset := range offsets {
+		out, err := SegmentArgsort(values, offset)
+		if err != nil {
+			t.Error(err)
+		}
+		if !out.Eq(target) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+		}
+
+		sorted, err := SegmentSort(values, offset)
+		if err != nil {
+			t.Error(err)
+		}
+		if !sorted.Eq(sortedTarget) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", sortedTarget, sorted)
+		}
+	}
+
+	// Illegal offsets
+	illegal := [][]int{{1, 3}, {0, 4, 3}, {0, 9}}
+	for _, backing := range illegal {
+		offset := tensor.NewDense(
+			tensor.Int,
+			[]int{len(backing)},
+			tensor.WithBacking(backing),
+		)
+		if _, err := SegmentArgsort(values, offset); err == nil {
+			t.Errorf("expected error for offsets %v", backing)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package top
+
+import (
+	"fmt"
+	"math"
+
+	"gorgonia.org/tensor"
+)
+
+// Softmax computes the softmax of t along axis:
+//
+//	out[..., i, ...] = exp(t[..., i, ...]) / sum_j exp(t[..., j, ...])
+//
+// The maximum along axis is subtracted before exponentiating, so that
+// Softmax does not overflow for large inputs. The returned tensor has
+// the same shape and type as t. Softmax works on tensors of type
+// float64 or float32.
+func Softmax(t tensor.Tensor, axis int) (tensor.Tensor, error) {
+	out, err := softmaxAlong(t, axis, false)
+	if err != nil {
+		return nil, fmt.Errorf("softmax: %v", err)
+	}
+	return out, nil
+}
+
+// LogSoftmax computes the logarithm of the softmax of t along axis:
+//
+//	out[..., i, ...] = t[..., i, ...] - log(sum_j exp(t[..., j, ...]))
+//
+// LogSoftmax is more accurate than taking the logarithm of Softmax,
+// and should be preferred when computing log-probabilities from
+// logits. The returned tensor has the same shape and type as t.
+// LogSoftmax works on tensors of type float64 or float32.
+func LogSoftmax(t tensor.Tensor, axis int) (tensor.Tensor, error) {
+	out, err := softmaxAlong(t, axis, true)
+	if err != nil {
+		return nil, fmt.Errorf("logSoftmax: %v", err)
+	}
+	return out, nil
+}
+
+// LogSumExp computes log(sum(exp(t))) along axis without overflow. If
+// keepdims is true, the returned tensor has the same number of
+// dimensions as t with axis having size 1. Otherwise, axis is removed
+// from the shape of the returned tensor. The log-sum-exp of an empty
+// row is -Inf.
+//
+// LogSumExp works on tensors of type float64 or float32, and the
+// returned tensor has the same type as t.
+func LogSumExp(t tensor.Tensor, axis int, keepdims bool) (tensor.Tensor,
+	error) {
+	if err := checkSoftmaxArgs(t, axis); err != nil {
+		return nil, fmt.Errorf("logSumExp: %v", err)
+	}
+
+	starts, stride := axisRows(t.Shape(), axis)
+	n := t.Shape()[axis]
+	shape := reducedShape(t.Shape(), axis, keepdims)
+
+	switch t.Dtype() {
+	case tensor.Float64:
+		data, _ := float64Data(t)
+		return newTensor(shape, logSumExpF64(data, starts, stride, n)), nil
+
+	default:
+		data, _ := float32Data(t)
+		lse := logSumExpF32(data, starts, stride, n)
+		out := make([]float32, len(lse))
+		for i := range lse {
+			out[i] = float32(lse[i])
+		}
+		return newTensor(shape, out), nil
+	}
+}
+
+// softmaxAlong computes the softmax of t along axis, or the log-softmax
+// if log is true
+func softmaxAlong(t tensor.Tensor, axis int, log bool) (tensor.Tensor,
+	error) {
+	if err := checkSoftmaxArgs(t, axis); err != nil {
+		return nil, err
+	}
+
+	starts, stride := axisRows(t.Shape(), axis)
+	n := t.Shape()[axis]
+
+	switch t.Dtype() {
+	case tensor.Float64:
+		data, _ := float64Data(t)
+		lse := logSumExpF64(data, starts, stride, n)
+
+		out := make([]float64, len(data))
+		for r, start := range starts {
+			for j := 0; j < n; j++ {
+				i := start + j*stride
+				out[i] = data[i] - lse[r]
+				if !log {
+					out[i] = math.Exp(out[i])
+				}
+			}
+		}
+		return newTensor(t.Shape().Clone(), out), nil
+
+	default:
+		data, _ := float32Data(t)
+		lse := logSumExpF32(data, starts, stride, n)
+
+		out := make([]float32, len(data))
+		for r, start := range starts {
+			for j := 0; j < n; j++ {
+				i := start + j*stride
+				v := float64(data[i]) - lse[r]
+				if !log {
+					v = math.Exp(v)
+				}
+				out[i] = float32(v)
+			}
+		}
+		return newTensor(t.Shape().Clone(), out), nil
+	}
+}
+
+// checkSoftmaxArgs checks that t is a floating point tensor and that
+// axis is a valid axis of t
+func checkSoftmaxArgs(t tensor.Tensor, axis int) error {
+	if t.Dtype() != tensor.Float64 && t.Dtype() != tensor.Float32 {
+		return fmt.Errorf("expected tensor of type %v or %v but got %v",
+			tensor.Float64, tensor.Float32, t.Dtype())
+	}
+	if axis < 0 || axis >= len(t.Shape()) {
+		return fmt.Errorf("axis out of range [%v] for tensor with %v "+
+			"dimensions", axis, len(t.Shape()))
+	}
+	return nil
+}
+
+// logSumExpF64 returns the log-sum-exp of each row of data, where the
+// rows are given by starts and stride as returned by axisRows and each
+// row has n elements
+func logSumExpF64(data []float64, starts []int, stride, n int) []float64 {
+	out := make([]float64, len(starts))
+	for r, start := range starts {
+		max := math.Inf(-1)
+		for j := 0; j < n; j++ {
+			max = math.Max(max, data[start+j*stride])
+		}
+		// Rows of -Inf have a log-sum-exp of -Inf, and rows containing
+		// +Inf have a log-sum-exp of +Inf
+		if math.IsInf(max, 0) {
+			out[r] = max
+			continue
+		}
+
+		sum := 0.0
+		for j := 0; j < n; j++ {
+			sum += math.Exp(data[start+j*stride] - max)
+		}
+		out[r] = max + math.Log(sum)
+	}
+	return out
+}
+
+// logSumExpF32 is the float32 version of logSumExpF64. Sums are
+// accumulated in float64.
+func logSumExpF32(data []float32, starts []int, stride, n int) []float64 {
+	out := make([]float64, len(starts))
+	for r, start := range starts {
+		max := math.Inf(-1)
+		for j := 0; j < n; j++ {
+			max = math.Max(max, float64(data[start+j*stride]))
+		}
+		if math.IsInf(max, 0) {
+			out[r] = max
+			continue
+		}
+
+		sum := 0.0
+		for j := 0; j < n; j++ {
+			sum += math.Exp(float64(data[start+j*stride]) - max)
+		}
+		out[r] = max + math.Log(sum)
+	}
+	return out
+}
+
+// SoftmaxB is the backward pass of Softmax. Given the gradient grad with
+// respect to the output of Softmax, and that output, SoftmaxB returns
+// the gradient with respect to the input of Softmax:
+//
+//	dt[..., i, ...] = out[..., i, ...] * (grad[..., i, ...] -
+//		sum_j grad[..., j, ...] * out[..., j, ...])
+//
+// The grad and output tensors must have the same shape and type.
+func SoftmaxB(grad, output tensor.Tensor, axis int) (tensor.Tensor, error) {
+	g, y, err := softmaxBArgs(grad, output, axis)
+	if err != nil {
+		return nil, fmt.Errorf("softmaxB: %v", err)
+	}
+
+	starts, stride := axisRows(output.Shape(), axis)
+	n := output.Shape()[axis]
+	out := make([]float64, len(y))
+	for _, start := range starts {
+		dot := 0.0
+		for j := 0; j < n; j++ {
+			i := start + j*stride
+			dot += g[i] * y[i]
+		}
+		for j := 0; j < n; j++ {
+			i := start + j*stride
+			out[i] = y[i] * (g[i] - dot)
+		}
+	}
+	return fromFloat64Data(output.Dtype(), output.Shape().Clone(), out), nil
+}
+
+// LogSoftmaxB is the backward pass of LogSoftmax. Given the gradient
+// grad with respect to the output of LogSoftmax, and that output,
+// LogSoftmaxB returns the gradient with respect to the input of
+// LogSoftmax:
+//
+//	dt[..., i, ...] = grad[..., i, ...] -
+//		exp(out[..., i, ...]) * sum_j grad[..., j, ...]
+//
+// The grad and output tensors must have the same shape and type.
+func LogSoftmaxB(grad, output tensor.Tensor, axis int) (tensor.Tensor,
+	error) {
+	g, y, err := softmaxBArgs(grad, output, axis)
+	if err != nil {
+		return nil, fmt.Errorf("logSoftmaxB: %v", err)
+	}
+
+	starts, stride := axisRows(output.Shape(), axis)
+	n := output.Shape()[axis]
+	out := make([]float64, len(y))
+	for _, start := range starts {
+		sum := 0.0
+		for j := 0; j < n; j++ {
+			sum += g[start+j*stride]
+		}
+		for j := 0; j < n; j++ {
+			i := start + j*stride
+			out[i] = g[i] - math.Exp(y[i])*sum
+		}
+	}
+	return fromFloat64Data(output.Dtype(), output.Shape().Clone(), out), nil
+}
+
+// softmaxBArgs checks the arguments of SoftmaxB and LogSoftmaxB,
+// returning the data of grad and output
+func softmaxBArgs(grad, output tensor.Tensor, axis int) ([]float64,
+	[]float64, error) {
+	if err := checkSoftmaxArgs(output, axis); err != nil {
+		return nil, nil, err
+	}
+	if grad.Dtype() != output.Dtype() {
+		return nil, nil, fmt.Errorf("grad and output must have the same "+
+			"type but got grad=%v and output=%v", grad.Dtype(),
+			output.Dtype())
+	}
+	if !grad.Shape().Eq(output.Shape()) {
+		return nil, nil, fmt.Errorf("grad and output must have the same "+
+			"shape but got grad=%v and output=%v", grad.Shape(),
+			output.Shape())
+	}
+
+	g, err := toFloat64Data(grad)
+	if err != nil {
+		return nil, nil, err
+	}
+	y, err := toFloat64Data(output)
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, y, nil
+}
+
+// LogSumExpB is the backward pass of LogSumExp. Given the gradient grad
+// with respect to the output of LogSumExp, the input t of LogSumExp and
+// its output, LogSumExpB returns the gradient with respect to t, which
+// is grad multiplied by the softmax of t along axis.
+//
+// The grad and output tensors must have the same shape, which may be
+// the shape resulting from calling LogSumExp with keepdims either true
+// or false. All tensors must have the same type.
+func LogSumExpB(grad, t, output tensor.Tensor, axis int) (tensor.Tensor,
+	error) {
+	if err := checkSoftmaxArgs(t, axis); err != nil {
+		return nil, fmt.Errorf("logSumExpB: %v", err)
+	}
+	if grad.Dtype() != t.Dtype() || output.Dtype() != t.Dtype() {
+		return nil, fmt.Errorf("logSumExpB: expected grad and output of "+
+			"type %v but got grad=%v and output=%v", t.Dtype(),
+			grad.Dtype(), output.Dtype())
+	}
+	if !grad.Shape().Eq(output.Shape()) {
+		return nil, fmt.Errorf("logSumExpB: grad and output must have "+
+			"the same shape but got grad=%v and output=%v", grad.Shape(),
+			output.Shape())
+	}
+	if !output.Shape().Eq(reducedShape(t.Shape(), axis, true)) &&
+		!output.Shape().Eq(reducedShape(t.Shape(), axis, false)) {
+		return nil, fmt.Errorf("logSumExpB: output of shape %v is not the "+
+			"reduction of shape %v along axis %v", output.Shape(),
+			t.Shape(), axis)
+	}
+
+	g, _ := toFloat64Data(grad)
+	lse, _ := toFloat64Data(output)
+	x, _ := toFloat64Data(t)
+
+	starts, stride := axisRows(t.Shape(), axis)
+	n := t.Shape()[axis]
+	out := make([]float64, len(x))
+	for r, start := range starts {
+		for j := 0; j < n; j++ {
+			i := start + j*stride
+			out[i] = g[r] * math.Exp(x[i]-lse[r])
+		}
+	}
+	return fromFloat64Data(t.Dtype(), t.Shape().Clone(), out), nil
+}
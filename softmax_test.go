@@ -0,0 +1,217 @@
+package top
+
+import (
+	"math"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+// closeTo returns whether the float64 or float32 tensors a and b have
+// the same shape and their elements are within tol of each other
+func closeTo(a, b tensor.Tensor, tol float64) bool {
+	if !a.Shape().Eq(b.Shape()) || a.Dtype() != b.Dtype() {
+		return false
+	}
+	aData, _ := toFloat64Data(a)
+	bData, _ := toFloat64Data(b)
+	for i := range aData {
+		if math.Abs(aData[i]-bData[i]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSoftmax(t *testing.T) {
+	// The second row would overflow without subtracting the maximum
+	in := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{1, 2, 3, 1000, 1001, 1002}),
+	)
+	e := math.Exp(1)
+	norm := 1 + e + e*e
+	target := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{
+			1 / norm, e / norm, e * e / norm,
+			1 / norm, e / norm, e * e / norm,
+		}),
+	)
+
+	out, err := Softmax(in, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(out, target, 1e-12) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	out, err = LogSoftmax(in, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logNorm := math.Log(norm)
+	target = tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{
+			-logNorm, 1 - logNorm, 2 - logNorm,
+			-logNorm, 1 - logNorm, 2 - logNorm,
+		}),
+	)
+	if !closeTo(out, target, 1e-12) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	// Softmax along the first axis
+	in = tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 2},
+		tensor.WithBacking([]float32{0, 1, 0, 1}),
+	)
+	target = tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 2},
+		tensor.WithBacking([]float32{0.5, 0.5, 0.5, 0.5}),
+	)
+	out, err = Softmax(in, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(out, target, 1e-7) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	if _, err := Softmax(in, 2); err == nil {
+		t.Error("expected error for axis out of range")
+	}
+	ints := tensor.New(tensor.WithShape(2), tensor.WithBacking([]int{1, 2}))
+	if _, err := LogSoftmax(ints, 0); err == nil {
+		t.Error("expected error for int tensor")
+	}
+}
+
+func TestLogSumExp(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 3},
+		tensor.WithBacking([]float32{
+			0, 0, 0,
+			float32(math.Inf(-1)), float32(math.Inf(-1)), 500,
+		}),
+	)
+
+	out, err := LogSumExp(in, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 1},
+		tensor.WithBacking([]float32{float32(math.Log(3)), 500}),
+	)
+	if !closeTo(out, target, 1e-5) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	out, err = LogSumExp(in, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target = tensor.NewDense(
+		tensor.Float32,
+		[]int{3},
+		tensor.WithBacking([]float32{0, 0, 500}),
+	)
+	if !closeTo(out, target, 1e-5) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	// A row of -Inf has a log-sum-exp of -Inf
+	in = tensor.NewDense(
+		tensor.Float64,
+		[]int{2},
+		tensor.WithBacking([]float64{math.Inf(-1), math.Inf(-1)}),
+	)
+	out, err = LogSumExp(in, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := out.ScalarValue().(float64); !math.IsInf(v, -1) {
+		t.Errorf("expected -Inf but got %v", v)
+	}
+}
+
+func TestSoftmaxB(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{0.5, -1, 2, 0, 0, 0}),
+	)
+	grad := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{1, 0, 0, 1, 2, 3}),
+	)
+
+	// dx = grad - softmax(x) * sum(grad)
+	out, err := LogSoftmax(in, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dx, err := LogSoftmaxB(grad, out, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	probs, _ := Softmax(in, 1)
+	p := probs.Data().([]float64)
+	g := grad.Data().([]float64)
+	sums := []float64{1, 6}
+	targetData := make([]float64, len(p))
+	for i := range targetData {
+		targetData[i] = g[i] - p[i]*sums[i/3]
+	}
+	target := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking(targetData),
+	)
+	if !closeTo(dx, target, 1e-12) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, dx)
+	}
+
+	// The gradient of the softmax is zero for a constant gradient
+	ones := tensor.Ones(tensor.Float64, 2, 3)
+	dx, err = SoftmaxB(ones, probs, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(dx, zerosLike(in), 1e-12) {
+		t.Errorf("expected zero gradient but got \n%v", dx)
+	}
+
+	// The gradient of the log-sum-exp is the softmax
+	lse, err := LogSumExp(in, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dx, err = LogSumExpB(tensor.Ones(tensor.Float64, 2), in, lse, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(dx, probs, 1e-12) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", probs, dx)
+	}
+
+	if _, err := SoftmaxB(tensor.Ones(tensor.Float64, 3, 2), probs,
+		1); err == nil {
+		t.Error("expected error for grad of wrong shape")
+	}
+	if _, err := LogSumExpB(tensor.Ones(tensor.Float64, 3), in,
+		tensor.Ones(tensor.Float64, 3), 1); err == nil {
+		t.Error("expected error for output of wrong shape")
+	}
+}
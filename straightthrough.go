@@ -0,0 +1,71 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// StraightThrough returns an operation whose forward pass is that of
+// forward, but whose vector-Jacobian and Jacobian-vector products are
+// those of surrogate, evaluated at the same inputs and attributes. This
+// gives operations which are not differentiable, or whose gradients
+// vanish almost everywhere, a surrogate gradient. For example, the
+// straight-through estimator of rounding uses the identity gradient:
+//
+//	round, _ := Lookup("round")
+//	identity, _ := Lookup("identity")
+//	op, err := StraightThrough(round, identity)
+//	...
+//	err = Register(op)
+//
+// The returned operation is called "straightThrough(forward,
+// surrogate)" with the names of forward and surrogate, and may be
+// renamed before it is registered. Both operations must have the same
+// arity, and surrogate must be differentiable.
+func StraightThrough(forward, surrogate OpDef) (OpDef, error) {
+	if forward.Forward == nil {
+		return OpDef{}, fmt.Errorf("straightThrough: operation %q must "+
+			"have a forward function", forward.Name)
+	}
+	if !surrogate.Differentiable() {
+		return OpDef{}, fmt.Errorf("straightThrough: surrogate %q must be "+
+			"differentiable", surrogate.Name)
+	}
+	if forward.Arity != surrogate.Arity {
+		return OpDef{}, fmt.Errorf("straightThrough: operations must have "+
+			"the same arity but %q has arity %v and %q has arity %v",
+			forward.Name, forward.Arity, surrogate.Name, surrogate.Arity)
+	}
+
+	op := OpDef{
+		Name: fmt.Sprintf("straightThrough(%v, %v)", forward.Name,
+			surrogate.Name),
+		Arity:   forward.Arity,
+		Forward: forward.Forward,
+
+		// The products of the surrogate may depend on its own output, so
+		// the surrogate is evaluated rather than using the output of the
+		// forward pass
+		VJP: func(grad tensor.Tensor, inputs []tensor.Tensor,
+			output tensor.Tensor, attrs Attrs) ([]tensor.Tensor, error) {
+			out, err := surrogate.Forward(inputs, attrs)
+			if err != nil {
+				return nil, err
+			}
+			return surrogate.VJP(grad, inputs, out, attrs)
+		},
+	}
+
+	if surrogate.JVP != nil {
+		op.JVP = func(tangents, inputs []tensor.Tensor, output tensor.Tensor,
+			attrs Attrs) (tensor.Tensor, error) {
+			out, err := surrogate.Forward(inputs, attrs)
+			if err != nil {
+				return nil, err
+			}
+			return surrogate.JVP(tangents, inputs, out, attrs)
+		}
+	}
+	return op, nil
+}
@@ -0,0 +1,94 @@
+package top
+
+import (
+	"math"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestStraightThrough(t *testing.T) {
+	round, _ := Lookup("round")
+	identity, _ := Lookup("identity")
+	op, err := StraightThrough(round, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Name != "straightThrough(round, identity)" {
+		t.Errorf("unexpected name %q", op.Name)
+	}
+
+	x := tensor.NewDense(
+		tensor.Float64,
+		[]int{4},
+		tensor.WithBacking([]float64{-1.6, -0.2, 0.5, 2.4}),
+	)
+	out, err := op.Forward([]tensor.Tensor{x}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := tensor.NewDense(
+		tensor.Float64,
+		[]int{4},
+		tensor.WithBacking([]float64{-2, 0, 1, 2}),
+	)
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	// The gradient of the identity is passed straight through
+	grad := tensor.NewDense(
+		tensor.Float64,
+		[]int{4},
+		tensor.WithBacking([]float64{1, 2, 3, 4}),
+	)
+	dx, err := op.VJP(grad, []tensor.Tensor{x}, out, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dx[0].Eq(grad) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", grad, dx[0])
+	}
+	jvp, err := op.JVP([]tensor.Tensor{grad}, []tensor.Tensor{x}, out, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jvp.Eq(grad) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", grad, jvp)
+	}
+
+	// The surrogate is evaluated at the inputs to compute its gradient,
+	// since the gradient of tanh depends on its own output
+	tanh, _ := Lookup("tanh")
+	op, err = StraightThrough(round, tanh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dx, err = op.VJP(grad, []tensor.Tensor{x}, out, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetData := make([]float64, 4)
+	for i, v := range x.Data().([]float64) {
+		y := math.Tanh(v)
+		targetData[i] = float64(i+1) * (1 - y*y)
+	}
+	target = tensor.NewDense(
+		tensor.Float64,
+		[]int{4},
+		tensor.WithBacking(targetData),
+	)
+	if !closeTo(dx[0], target, 1e-12) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, dx[0])
+	}
+
+	// Errors
+	add, _ := Lookup("add")
+	if _, err := StraightThrough(round, add); err == nil {
+		t.Error("expected error for operations of different arities")
+	}
+	argsort, _ := Lookup("argsort")
+	if _, err := StraightThrough(identity, argsort); err == nil {
+		t.Error("expected error for non-differentiable surrogate")
+	}
+}
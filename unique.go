@@ -0,0 +1,253 @@
+package top
+
+import (
+	"fmt"
+	"sort"
+
+	"gorgonia.org/tensor"
+)
+
+// NoAxis may be used as the axis argument of functions such as Unique
+// to indicate that the function should operate on the flattened
+// tensor rather than along a specific axis.
+const NoAxis int = -1
+
+// Unique returns the unique elements of t. If axis is NoAxis, the
+// unique elements of the flattened t are returned in a 1D tensor.
+// Otherwise, the unique slices of t along axis are returned, where
+// each slice consists of all elements of t with the same index along
+// axis. In this case, the returned tensor has the same shape as t
+// except along axis.
+//
+// If sorted is true, the unique elements (or slices, compared
+// lexicographically) are returned in ascending order. Otherwise, they
+// are returned in the order of their first occurrence in t.
+//
+// If returnInverse is true, an int tensor of indices is also
+// returned such that element (or slice) i of t is equal to element
+// (or slice) inverse[i] of the unique values. If axis is NoAxis, the
+// inverse tensor has the same shape as t, otherwise it is a 1D tensor
+// with t.Shape()[axis] elements. If t is a 1D tensor, gathering the
+// unique values along axis 0 at the inverse indices rebuilds t. If
+// returnCounts is true, a 1D int tensor holding the number of times
+// each unique element (or slice) occurs in t is also returned. If
+// returnInverse or returnCounts is false, the corresponding returned
+// tensor is nil.
+//
+// Unique works on tensors of type float64, float32, or any int type.
+// If t has an integer type (e.g. uint32), the returned values will be
+// converted to int and stored in a tensor of type tensor.Int.
+//
+// This implementation is heavily based on the PyTorch implementation.
+// See PyTorch's documentation for more details and usage:
+// https://pytorch.org/docs/stable/generated/torch.unique.html
+func Unique(t tensor.Tensor, sorted, returnInverse, returnCounts bool,
+	axis int) (tensor.Tensor, tensor.Tensor, tensor.Tensor, error) {
+	u, err := newUniqueUnits(t, axis)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unique: %v", err)
+	}
+
+	// Stably sort the units so that equal units are consecutive, with
+	// the first occurrence of each unit first in its group
+	order := argSort(u)
+
+	groups := make([][]int, 0)
+	for i, unit := range order {
+		if i == 0 || u.compare(order[i-1], unit) != 0 {
+			groups = append(groups, []int{unit})
+		} else {
+			groups[len(groups)-1] = append(groups[len(groups)-1], unit)
+		}
+	}
+
+	// Order the groups by their first occurrence if needed. Since the
+	// sort is stable, the first unit of each group is its first
+	// occurrence in t.
+	if !sorted {
+		first := make([]int, len(groups))
+		for i := range groups {
+			first[i] = groups[i][0]
+		}
+		ind := argSort(sort.IntSlice(first))
+
+		ordered := make([][]int, len(groups))
+		for i := range ind {
+			ordered[i] = groups[ind[i]]
+		}
+		groups = ordered
+	}
+
+	values, inverse, counts, err := u.result(groups, returnInverse,
+		returnCounts)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unique: %v", err)
+	}
+	return values, inverse, counts, nil
+}
+
+// UniqueConsecutive eliminates all but the first element (or slice)
+// from every group of consecutive equal elements (or slices) of t. The
+// arguments and returned tensors are the same as those of Unique, with
+// the unique values being returned in the order in which they occur
+// in t.
+//
+// See PyTorch's documentation for more details and usage:
+// https://pytorch.org/docs/stable/generated/torch.unique_consecutive.html
+func UniqueConsecutive(t tensor.Tensor, returnInverse, returnCounts bool,
+	axis int) (tensor.Tensor, tensor.Tensor, tensor.Tensor, error) {
+	u, err := newUniqueUnits(t, axis)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("uniqueConsecutive: %v", err)
+	}
+
+	groups := make([][]int, 0)
+	for unit := 0; unit < u.Len(); unit++ {
+		if unit == 0 || u.compare(unit-1, unit) != 0 {
+			groups = append(groups, []int{unit})
+		} else {
+			groups[len(groups)-1] = append(groups[len(groups)-1], unit)
+		}
+	}
+
+	values, inverse, counts, err := u.result(groups, returnInverse,
+		returnCounts)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("uniqueConsecutive: %v", err)
+	}
+	return values, inverse, counts, nil
+}
+
+// uniqueUnits implements sort.Interface to sort the units compared by
+// Unique, which are either the elements of a flattened tensor or the
+// slices of a tensor along an axis. Since uniqueUnits is only sorted
+// indirectly through argSort, Swap does nothing.
+type uniqueUnits struct {
+	t       tensor.Tensor
+	axis    int
+	n       int // Number of units
+	inner   int // Number of elements after axis in each unit
+	outer   int // Number of elements before axis in each unit
+	cmp     func(i, j int) int
+	indices [][]int // Row-major indices of the elements in each unit
+}
+
+// newUniqueUnits returns a new uniqueUnits for t along axis
+func newUniqueUnits(t tensor.Tensor, axis int) (*uniqueUnits, error) {
+	if axis != NoAxis && (axis < 0 || axis >= len(t.Shape())) {
+		return nil, fmt.Errorf("axis out of range [%v] for tensor with "+
+			"%v dimensions", axis, len(t.Shape()))
+	}
+
+	cmp, err := comparer(t, true)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &uniqueUnits{t: t, axis: axis, cmp: cmp}
+	if axis == NoAxis {
+		u.n = t.Shape().TotalSize()
+		u.inner, u.outer = 1, 1
+	} else {
+		shape := t.Shape()
+		u.n = shape[axis]
+		u.inner = tensor.ProdInts([]int(shape[axis+1:]))
+		u.outer = tensor.ProdInts([]int(shape[:axis]))
+	}
+
+	// Compute the row-major indices of the elements in each unit
+	u.indices = make([][]int, u.n)
+	for unit := range u.indices {
+		u.indices[unit] = make([]int, 0, u.inner*u.outer)
+		for o := 0; o < u.outer; o++ {
+			for in := 0; in < u.inner; in++ {
+				u.indices[unit] = append(u.indices[unit],
+					(o*u.n+unit)*u.inner+in)
+			}
+		}
+	}
+
+	return u, nil
+}
+
+// compare lexicographically compares units i and j, returning a
+// positive value if unit i is larger, a negative value if unit j is
+// larger, and 0 if the units are equal.
+func (u *uniqueUnits) compare(i, j int) int {
+	for k := range u.indices[i] {
+		if c := u.cmp(u.indices[i][k], u.indices[j][k]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Len implements the interface sort.Interface
+func (u *uniqueUnits) Len() int { return u.n }
+
+// Less implements the interface sort.Interface
+func (u *uniqueUnits) Less(i, j int) bool { return u.compare(i, j) < 0 }
+
+// Swap implements the interface sort.Interface
+func (u *uniqueUnits) Swap(i, j int) {}
+
+// result constructs the values, inverse, and counts tensors returned
+// by Unique, given the groups of equal units in the order in which
+// they should be returned.
+func (u *uniqueUnits) result(groups [][]int, returnInverse,
+	returnCounts bool) (tensor.Tensor, tensor.Tensor, tensor.Tensor, error) {
+	// Take the first unit from each group. The elements of each unit
+	// must be placed in their correct row-major positions in the
+	// returned tensor.
+	indices := make([]int, len(groups)*u.inner*u.outer)
+	for g := range groups {
+		unit := u.indices[groups[g][0]]
+		k := 0
+		for o := 0; o < u.outer; o++ {
+			for in := 0; in < u.inner; in++ {
+				indices[(o*len(groups)+g)*u.inner+in] = unit[k]
+				k++
+			}
+		}
+	}
+	data, err := take(u.t, indices)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var shape tensor.Shape
+	if u.axis == NoAxis {
+		shape = tensor.Shape{len(groups)}
+	} else {
+		shape = u.t.Shape().Clone()
+		shape[u.axis] = len(groups)
+	}
+	values := newTensor(shape, data)
+
+	var inverse tensor.Tensor
+	if returnInverse {
+		inv := make([]int, u.n)
+		for g := range groups {
+			for _, unit := range groups[g] {
+				inv[unit] = g
+			}
+		}
+
+		if u.axis == NoAxis && !u.t.Shape().IsScalar() {
+			inverse = newTensor(u.t.Shape().Clone(), inv)
+		} else {
+			inverse = newTensor(tensor.Shape{u.n}, inv)
+		}
+	}
+
+	var counts tensor.Tensor
+	if returnCounts {
+		c := make([]int, len(groups))
+		for g := range groups {
+			c[g] = len(groups[g])
+		}
+		counts = newTensor(tensor.Shape{len(groups)}, c)
+	}
+
+	return values, inverse, counts, nil
+}
@@ -0,0 +1,151 @@
+package top
+
+import (
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestUnique(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Int64,
+		[]int{7},
+		tensor.WithBacking([]int64{3, 1, 3, 2, 1, 3, 0}),
+	)
+
+	// Sorted unique elements
+	values, inverse, counts, err := Unique(in, true, true, true, NoAxis)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets := []*tensor.Dense{
+		tensor.NewDense(tensor.Int, []int{4},
+			tensor.WithBacking([]int{0, 1, 2, 3})),
+		tensor.NewDense(tensor.Int, []int{7},
+			tensor.WithBacking([]int{3, 1, 3, 2, 1, 3, 0})),
+		tensor.NewDense(tensor.Int, []int{4},
+			tensor.WithBacking([]int{1, 2, 1, 3})),
+	}
+	for i, out := range []tensor.Tensor{values, inverse, counts} {
+		if !out.Eq(targets[i]) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", targets[i], out)
+		}
+	}
+
+	// Unique elements in order of first occurrence
+	values, inverse, counts, err = Unique(in, false, true, true, NoAxis)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets = []*tensor.Dense{
+		tensor.NewDense(tensor.Int, []int{4},
+			tensor.WithBacking([]int{3, 1, 2, 0})),
+		tensor.NewDense(tensor.Int, []int{7},
+			tensor.WithBacking([]int{0, 1, 0, 2, 1, 0, 3})),
+		tensor.NewDense(tensor.Int, []int{4},
+			tensor.WithBacking([]int{3, 2, 1, 1})),
+	}
+	for i, out := range []tensor.Tensor{values, inverse, counts} {
+		if !out.Eq(targets[i]) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", targets[i], out)
+		}
+	}
+
+	// Tensors which are not requested are not returned
+	_, inverse, counts, err = Unique(in, true, false, false, NoAxis)
+	if err != nil {
+		t.Error(err)
+	}
+	if inverse != nil || counts != nil {
+		t.Error("expected nil inverse and counts")
+	}
+}
+
+func TestUniqueAxis(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float64,
+		[]int{4, 2},
+		tensor.WithBacking([]float64{1, 2, 0, 5, 1, 2, 0, 4}),
+	)
+
+	// Unique rows
+	values, inverse, counts, err := Unique(in, true, true, true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets := []*tensor.Dense{
+		tensor.NewDense(tensor.Float64, []int{3, 2},
+			tensor.WithBacking([]float64{0, 4, 0, 5, 1, 2})),
+		tensor.NewDense(tensor.Int, []int{4},
+			tensor.WithBacking([]int{2, 1, 2, 0})),
+		tensor.NewDense(tensor.Int, []int{3},
+			tensor.WithBacking([]int{1, 1, 2})),
+	}
+	for i, out := range []tensor.Tensor{values, inverse, counts} {
+		if !out.Eq(targets[i]) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", targets[i], out)
+		}
+	}
+
+	// Unique columns
+	values, _, _, err = Unique(in, true, false, false, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !values.Eq(in) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", in, values)
+	}
+}
+
+// TestUniqueGather tests if gathering the unique values along the
+// inverse indices rebuilds the input tensor
+func TestUniqueGather(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Float32,
+		[]int{8},
+		tensor.WithBacking([]float32{0.5, 2, 0.5, -1, 2, 2, 7, -1}),
+	)
+
+	values, inverse, _, err := Unique(in, true, true, false, NoAxis)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := Gather(values, 0, inverse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Eq(in) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", in, out)
+	}
+}
+
+func TestUniqueConsecutive(t *testing.T) {
+	in := tensor.NewDense(
+		tensor.Int,
+		[]int{8},
+		tensor.WithBacking([]int{1, 1, 2, 2, 3, 1, 1, 2}),
+	)
+
+	values, inverse, counts, err := UniqueConsecutive(in, true, true, NoAxis)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets := []*tensor.Dense{
+		tensor.NewDense(tensor.Int, []int{5},
+			tensor.WithBacking([]int{1, 2, 3, 1, 2})),
+		tensor.NewDense(tensor.Int, []int{8},
+			tensor.WithBacking([]int{0, 0, 1, 1, 2, 3, 3, 4})),
+		tensor.NewDense(tensor.Int, []int{5},
+			tensor.WithBacking([]int{2, 2, 1, 2, 1})),
+	}
+	for i, out := range []tensor.Tensor{values, inverse, counts} {
+		if !out.Eq(targets[i]) {
+			t.Errorf("expected: \n%v \nreceived: \n%v", targets[i], out)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package top
 import (
 	"fmt"
 	"math/rand"
+
+	"gorgonia.org/tensor"
 )
 
 // randInt returns a random int slice of length size
@@ -43,3 +45,439 @@ func anyIntToInt(integer interface{}) (int, error) {
 			"type", integer)
 	}
 }
+
+// isIntDtype returns whether dt is any of the integer data types
+func isIntDtype(dt tensor.Dtype) bool {
+	switch dt {
+	case tensor.Int, tensor.Int8, tensor.Int16, tensor.Int32, tensor.Int64,
+		tensor.Uint, tensor.Uint8, tensor.Uint16, tensor.Uint32, tensor.Uint64:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// backing returns the backing data of t. Scalar tensors return their
+// data as a scalar value rather than a slice, so the scalar is wrapped
+// in a slice of length one in that case.
+func backing(t tensor.Tensor) interface{} {
+	data := t.Data()
+	if !t.Shape().IsScalar() {
+		return data
+	}
+
+	switch d := data.(type) {
+	case float64:
+		return []float64{d}
+	case float32:
+		return []float32{d}
+	case int:
+		return []int{d}
+	case int8:
+		return []int8{d}
+	case int16:
+		return []int16{d}
+	case int32:
+		return []int32{d}
+	case int64:
+		return []int64{d}
+	case uint:
+		return []uint{d}
+	case uint8:
+		return []uint8{d}
+	case uint16:
+		return []uint16{d}
+	case uint32:
+		return []uint32{d}
+	case uint64:
+		return []uint64{d}
+	case bool:
+		return []bool{d}
+	default:
+		return data
+	}
+}
+
+// offsets returns the index into the backing data of t of each element
+// of t, in row-major order. For contiguous tensors this is simply
+// 0, 1, 2, ..., but views and transposed tensors may have their
+// elements spread out over the backing data.
+func offsets(t tensor.Tensor) []int {
+	shape := t.Shape()
+	strides := t.Strides()
+	size := shape.TotalSize()
+
+	out := make([]int, size)
+	if len(shape) == 0 || size == 0 {
+		return out
+	}
+
+	coords := make([]int, len(shape))
+	offset := 0
+	for i := 0; i < size; i++ {
+		out[i] = offset
+
+		// Increment the coordinates, carrying into earlier dimensions
+		for dim := len(shape) - 1; dim >= 0; dim-- {
+			coords[dim]++
+			offset += strides[dim]
+			if coords[dim] < shape[dim] {
+				break
+			}
+			offset -= coords[dim] * strides[dim]
+			coords[dim] = 0
+		}
+	}
+	return out
+}
+
+// isContiguous returns whether the elements of t are laid out in
+// row-major order in its backing data.
+func isContiguous(t tensor.Tensor) bool {
+	shape := t.Shape()
+	if shape.IsScalar() {
+		return true
+	}
+
+	strides := t.Strides()
+	expected := shape.CalcStrides()
+	for i := range shape {
+		// Strides along dimensions of size 1 are never used
+		if shape[i] != 1 && strides[i] != expected[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// float64Data returns the elements of a float64 tensor in row-major
+// order. The returned slice may share memory with t and so should
+// not be modified.
+func float64Data(t tensor.Tensor) ([]float64, error) {
+	data, ok := backing(t).([]float64)
+	if !ok {
+		return nil, fmt.Errorf("float64Data: expected tensor of type "+
+			"%v but got %v", tensor.Float64, t.Dtype())
+	}
+	if isContiguous(t) {
+		return data[:t.Shape().TotalSize()], nil
+	}
+
+	out := make([]float64, t.Shape().TotalSize())
+	for i, j := range offsets(t) {
+		out[i] = data[j]
+	}
+	return out, nil
+}
+
+// float32Data returns the elements of a float32 tensor in row-major
+// order. The returned slice may share memory with t and so should
+// not be modified.
+func float32Data(t tensor.Tensor) ([]float32, error) {
+	data, ok := backing(t).([]float32)
+	if !ok {
+		return nil, fmt.Errorf("float32Data: expected tensor of type "+
+			"%v but got %v", tensor.Float32, t.Dtype())
+	}
+	if isContiguous(t) {
+		return data[:t.Shape().TotalSize()], nil
+	}
+
+	out := make([]float32, t.Shape().TotalSize())
+	for i, j := range offsets(t) {
+		out[i] = data[j]
+	}
+	return out, nil
+}
+
+// boolData returns the elements of a bool tensor in row-major
+// order. The returned slice may share memory with t and so should
+// not be modified.
+func boolData(t tensor.Tensor) ([]bool, error) {
+	data, ok := backing(t).([]bool)
+	if !ok {
+		return nil, fmt.Errorf("boolData: expected tensor of type "+
+			"%v but got %v", tensor.Bool, t.Dtype())
+	}
+	if isContiguous(t) {
+		return data[:t.Shape().TotalSize()], nil
+	}
+
+	out := make([]bool, t.Shape().TotalSize())
+	for i, j := range offsets(t) {
+		out[i] = data[j]
+	}
+	return out, nil
+}
+
+// intData returns the elements of a tensor of any integer type in
+// row-major order, converting each element to an int. If t already
+// stores contiguous ints, the returned slice shares memory with t and
+// so should not be modified.
+func intData(t tensor.Tensor) ([]int, error) {
+	if data, ok := backing(t).([]int); ok && isContiguous(t) {
+		return data[:t.Shape().TotalSize()], nil
+	}
+
+	out := make([]int, t.Shape().TotalSize())
+	ind := offsets(t)
+	switch data := backing(t).(type) {
+	case []int:
+		for i, j := range ind {
+			out[i] = data[j]
+		}
+	case []int8:
+		for i, j := range ind {
+			out[i] = int(data[j])
+		}
+	case []int16:
+		for i, j := range ind {
+			out[i] = int(data[j])
+		}
+	case []int32:
+		for i, j := range ind {
+			out[i] = int(data[j])
+		}
+	case []int64:
+		for i, j := range ind {
+			out[i] = int(data[j])
+		}
+	case []uint:
+		for i, j := range ind {
+			out[i] = int(data[j])
+		}
+	case []uint8:
+		for i, j := range ind {
+			out[i] = int(data[j])
+		}
+	case []uint16:
+		for i, j := range ind {
+			out[i] = int(data[j])
+		}
+	case []uint32:
+		for i, j := range ind {
+			out[i] = int(data[j])
+		}
+	case []uint64:
+		for i, j := range ind {
+			out[i] = int(data[j])
+		}
+	default:
+		return nil, fmt.Errorf("intData: expected tensor of an integer "+
+			"type but got %v", t.Dtype())
+	}
+	return out, nil
+}
+
+// uint64Data returns the elements of a tensor of type uint or uint64
+// in row-major order as a []uint64. Unlike intData, values larger than
+// the largest int are kept intact.
+func uint64Data(t tensor.Tensor) ([]uint64, error) {
+	out := make([]uint64, t.Shape().TotalSize())
+	ind := offsets(t)
+	switch data := backing(t).(type) {
+	case []uint:
+		for i, j := range ind {
+			out[i] = uint64(data[j])
+		}
+	case []uint64:
+		for i, j := range ind {
+			out[i] = data[j]
+		}
+	default:
+		return nil, fmt.Errorf("uint64Data: expected tensor of type %v or "+
+			"%v but got %v", tensor.Uint, tensor.Uint64, t.Dtype())
+	}
+	return out, nil
+}
+
+// newTensor returns a new tensor with the argument shape and backing
+// data. If shape is the scalar shape, then a scalar tensor is
+// returned holding the single element of data.
+func newTensor(shape tensor.Shape, data interface{}) tensor.Tensor {
+	if !shape.IsScalar() {
+		return tensor.New(tensor.WithShape(shape...), tensor.WithBacking(data))
+	}
+
+	switch d := data.(type) {
+	case []float64:
+		return tensor.New(tensor.FromScalar(d[0]))
+	case []float32:
+		return tensor.New(tensor.FromScalar(d[0]))
+	case []int:
+		return tensor.New(tensor.FromScalar(d[0]))
+	case []bool:
+		return tensor.New(tensor.FromScalar(d[0]))
+	default:
+		panic(fmt.Sprintf("newTensor: unsupported backing type %T", data))
+	}
+}
+
+// axisRows returns the index of the first element of each row along
+// axis of a contiguous row-major tensor of the argument shape, along
+// with the distance between successive elements of each row. Rows are
+// ordered in row-major order of the dimensions other than axis, so
+// row i of the returned rows is the row which reduces to element i of
+// a tensor with axis removed.
+func axisRows(shape tensor.Shape, axis int) ([]int, int) {
+	inner := tensor.ProdInts([]int(shape[axis+1:]))
+	outer := tensor.ProdInts([]int(shape[:axis]))
+
+	starts := make([]int, 0, outer*inner)
+	for o := 0; o < outer; o++ {
+		for in := 0; in < inner; in++ {
+			starts = append(starts, o*shape[axis]*inner+in)
+		}
+	}
+	return starts, inner
+}
+
+// equalShapes returns whether shapes a and b have the same dimensions.
+// Unlike tensor.Shape.Eq, equalShapes does not consider a vector and a
+// column or row vector of the same length to be equal.
+func equalShapes(a, b tensor.Shape) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// reducedShape returns the shape resulting from reducing a tensor of
+// the argument shape along axis. If keepdims is true, the reduced axis
+// is kept with size 1, otherwise it is removed.
+func reducedShape(shape tensor.Shape, axis int, keepdims bool) tensor.Shape {
+	out := make(tensor.Shape, 0, len(shape))
+	for i, size := range shape {
+		if i != axis {
+			out = append(out, size)
+		} else if keepdims {
+			out = append(out, 1)
+		}
+	}
+	return out
+}
+
+// reshape returns a new tensor of the argument shape holding the
+// elements of t in row-major order. Tensors of any integer type are
+// converted to tensors of type tensor.Int. The returned tensor may
+// share memory with t.
+func reshape(t tensor.Tensor, shape tensor.Shape) (tensor.Tensor, error) {
+	if shape.TotalSize() != t.Shape().TotalSize() {
+		return nil, fmt.Errorf("reshape: cannot reshape tensor of shape %v "+
+			"to shape %v", t.Shape(), shape)
+	}
+
+	var data interface{}
+	var err error
+	switch {
+	case t.Dtype() == tensor.Float64:
+		data, err = float64Data(t)
+	case t.Dtype() == tensor.Float32:
+		data, err = float32Data(t)
+	case t.Dtype() == tensor.Bool:
+		data, err = boolData(t)
+	case isIntDtype(t.Dtype()):
+		data, err = intData(t)
+	default:
+		err = fmt.Errorf("reshape: unknown tensor type %v", t.Dtype())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newTensor(shape, data), nil
+}
+
+// take returns the elements of t at the argument indices into t in
+// row-major order. The returned slice is a []float64 for float64
+// tensors, a []float32 for float32 tensors, and a []int for tensors of
+// any integer type.
+func take(t tensor.Tensor, indices []int) (interface{}, error) {
+	switch {
+	case t.Dtype() == tensor.Float64:
+		data, _ := float64Data(t)
+		out := make([]float64, len(indices))
+		for i, j := range indices {
+			out[i] = data[j]
+		}
+		return out, nil
+
+	case t.Dtype() == tensor.Float32:
+		data, _ := float32Data(t)
+		out := make([]float32, len(indices))
+		for i, j := range indices {
+			out[i] = data[j]
+		}
+		return out, nil
+
+	case isIntDtype(t.Dtype()):
+		data, _ := intData(t)
+		out := make([]int, len(indices))
+		for i, j := range indices {
+			out[i] = data[j]
+		}
+		return out, nil
+
+	default:
+		return nil, fmt.Errorf("take: unknown tensor type %v", t.Dtype())
+	}
+}
+
+// toFloat64Data returns the elements of a float64 or float32 tensor in
+// row-major order as a []float64. The returned slice may share memory
+// with t and so should not be modified.
+func toFloat64Data(t tensor.Tensor) ([]float64, error) {
+	switch t.Dtype() {
+	case tensor.Float64:
+		return float64Data(t)
+
+	case tensor.Float32:
+		data, _ := float32Data(t)
+		out := make([]float64, len(data))
+		for i := range data {
+			out[i] = float64(data[i])
+		}
+		return out, nil
+
+	default:
+		return nil, fmt.Errorf("toFloat64Data: expected tensor of a "+
+			"floating point type but got %v", t.Dtype())
+	}
+}
+
+// numericFloat64Data returns the elements of t in row-major order as a
+// []float64, converting from float32 or any int type if needed. Unlike
+// toFloat64Data, int tensors are accepted.
+func numericFloat64Data(t tensor.Tensor) ([]float64, error) {
+	if !isIntDtype(t.Dtype()) {
+		return toFloat64Data(t)
+	}
+
+	data, _ := intData(t)
+	out := make([]float64, len(data))
+	for i := range data {
+		out[i] = float64(data[i])
+	}
+	return out, nil
+}
+
+// fromFloat64Data returns a new tensor of the argument floating point
+// type and shape holding the argument float64 data, which is converted
+// to float32 if needed. This is the inverse of toFloat64Data.
+func fromFloat64Data(dt tensor.Dtype, shape tensor.Shape,
+	data []float64) tensor.Tensor {
+	if dt == tensor.Float64 {
+		return newTensor(shape, data)
+	}
+
+	out := make([]float32, len(data))
+	for i := range data {
+		out[i] = float32(data[i])
+	}
+	return newTensor(shape, out)
+}
@@ -0,0 +1,199 @@
+package top
+
+import (
+	"fmt"
+
+	"gorgonia.org/tensor"
+)
+
+// Where returns a tensor whose elements are chosen from x where cond
+// is true and from y where cond is false:
+//
+//	out[i] = x[i] if cond[i] else y[i]
+//
+// The cond tensor must store bools. Each of x and y may be either a
+// tensor.Tensor or a scalar value, and cond, x, and y are broadcast
+// against each other following the NumPy broadcasting rules. The
+// shape of the returned tensor is the broadcast shape.
+//
+// Where works on x and y of type float64, float32, or any int type.
+// Floating point operands must have the same type. If x and y are of
+// an integer type (e.g. uint32 and int8), they will be converted to
+// int and the returned tensor will be of type tensor.Int.
+//
+// See NumPy's documentation for more details and usage:
+// https://numpy.org/doc/stable/reference/generated/numpy.where.html
+func Where(cond tensor.Tensor, x, y interface{}) (tensor.Tensor, error) {
+	if cond.Dtype() != tensor.Bool {
+		return nil, fmt.Errorf("where: cond must be of type %v but got %v",
+			tensor.Bool, cond.Dtype())
+	}
+
+	xT, err := whereOperand(x)
+	if err != nil {
+		return nil, fmt.Errorf("where: x: %v", err)
+	}
+	yT, err := whereOperand(y)
+	if err != nil {
+		return nil, fmt.Errorf("where: y: %v", err)
+	}
+
+	shape, err := broadcastShape(cond.Shape(), xT.Shape(), yT.Shape())
+	if err != nil {
+		return nil, fmt.Errorf("where: %v", err)
+	}
+
+	condData, err := boolData(cond)
+	if err != nil {
+		return nil, fmt.Errorf("where: %v", err)
+	}
+	condInd := broadcastIndices(cond.Shape(), shape)
+	xInd := broadcastIndices(xT.Shape(), shape)
+	yInd := broadcastIndices(yT.Shape(), shape)
+
+	switch {
+	case xT.Dtype() == tensor.Float64 && yT.Dtype() == tensor.Float64:
+		xData, _ := float64Data(xT)
+		yData, _ := float64Data(yT)
+		out := make([]float64, shape.TotalSize())
+		for i := range out {
+			if condData[condInd[i]] {
+				out[i] = xData[xInd[i]]
+			} else {
+				out[i] = yData[yInd[i]]
+			}
+		}
+		return newTensor(shape, out), nil
+
+	case xT.Dtype() == tensor.Float32 && yT.Dtype() == tensor.Float32:
+		xData, _ := float32Data(xT)
+		yData, _ := float32Data(yT)
+		out := make([]float32, shape.TotalSize())
+		for i := range out {
+			if condData[condInd[i]] {
+				out[i] = xData[xInd[i]]
+			} else {
+				out[i] = yData[yInd[i]]
+			}
+		}
+		return newTensor(shape, out), nil
+
+	case isIntDtype(xT.Dtype()) && isIntDtype(yT.Dtype()):
+		xData, _ := intData(xT)
+		yData, _ := intData(yT)
+		out := make([]int, shape.TotalSize())
+		for i := range out {
+			if condData[condInd[i]] {
+				out[i] = xData[xInd[i]]
+			} else {
+				out[i] = yData[yInd[i]]
+			}
+		}
+		return newTensor(shape, out), nil
+
+	default:
+		return nil, fmt.Errorf("where: cannot select between x of type %v "+
+			"and y of type %v", xT.Dtype(), yT.Dtype())
+	}
+}
+
+// WhereB is the backward pass of Where. Given the gradient grad of
+// some function with respect to the output of Where, WhereB returns
+// the gradients of that function with respect to x and y. The gradient
+// flows to x where cond is true and to y where cond is false.
+//
+// The arguments xShape and yShape are the shapes of the x and y
+// operands passed to Where, where scalar operands have the scalar
+// shape tensor.ScalarShape(). If an operand was broadcast in the
+// forward pass, its gradient is summed over the broadcast dimensions
+// so that each returned gradient has the shape of its operand.
+//
+// The grad tensor must store float64's, float32's, or any integer type.
+// If an integer type is used, the returned gradients will be of type
+// tensor.Int.
+func WhereB(grad, cond tensor.Tensor, xShape,
+	yShape tensor.Shape) (tensor.Tensor, tensor.Tensor, error) {
+	if cond.Dtype() != tensor.Bool {
+		return nil, nil, fmt.Errorf("whereb: cond must be of type %v but "+
+			"got %v", tensor.Bool, cond.Dtype())
+	}
+
+	shape, err := broadcastShape(cond.Shape(), xShape, yShape)
+	if err != nil {
+		return nil, nil, fmt.Errorf("whereb: %v", err)
+	}
+	if !shape.Eq(grad.Shape()) {
+		return nil, nil, fmt.Errorf("whereb: expected grad to have shape "+
+			"%v but got %v", shape, grad.Shape())
+	}
+
+	condData, err := boolData(cond)
+	if err != nil {
+		return nil, nil, fmt.Errorf("whereb: %v", err)
+	}
+	condInd := broadcastIndices(cond.Shape(), shape)
+	xInd := broadcastIndices(xShape, shape)
+	yInd := broadcastIndices(yShape, shape)
+
+	switch {
+	case grad.Dtype() == tensor.Float64:
+		gradData, _ := float64Data(grad)
+		dx := make([]float64, xShape.TotalSize())
+		dy := make([]float64, yShape.TotalSize())
+		for i := range gradData {
+			if condData[condInd[i]] {
+				dx[xInd[i]] += gradData[i]
+			} else {
+				dy[yInd[i]] += gradData[i]
+			}
+		}
+		return newTensor(xShape, dx), newTensor(yShape, dy), nil
+
+	case grad.Dtype() == tensor.Float32:
+		gradData, _ := float32Data(grad)
+		dx := make([]float32, xShape.TotalSize())
+		dy := make([]float32, yShape.TotalSize())
+		for i := range gradData {
+			if condData[condInd[i]] {
+				dx[xInd[i]] += gradData[i]
+			} else {
+				dy[yInd[i]] += gradData[i]
+			}
+		}
+		return newTensor(xShape, dx), newTensor(yShape, dy), nil
+
+	case isIntDtype(grad.Dtype()):
+		gradData, _ := intData(grad)
+		dx := make([]int, xShape.TotalSize())
+		dy := make([]int, yShape.TotalSize())
+		for i := range gradData {
+			if condData[condInd[i]] {
+				dx[xInd[i]] += gradData[i]
+			} else {
+				dy[yInd[i]] += gradData[i]
+			}
+		}
+		return newTensor(xShape, dx), newTensor(yShape, dy), nil
+
+	default:
+		return nil, nil, fmt.Errorf("whereb: cannot compute gradient of "+
+			"type %v", grad.Dtype())
+	}
+}
+
+// whereOperand returns the x or y operand of Where as a tensor,
+// converting scalar values to scalar tensors.
+func whereOperand(operand interface{}) (tensor.Tensor, error) {
+	switch o := operand.(type) {
+	case tensor.Tensor:
+		return o, nil
+
+	case float64, float32, int, int8, int16, int32, int64, uint, uint8,
+		uint16, uint32, uint64:
+		return tensor.New(tensor.FromScalar(o)), nil
+
+	default:
+		return nil, fmt.Errorf("whereOperand: unknown operand type %T",
+			operand)
+	}
+}
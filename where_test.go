@@ -0,0 +1,128 @@
+package top
+
+import (
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+func TestWhere(t *testing.T) {
+	cond := tensor.NewDense(
+		tensor.Bool,
+		[]int{2, 3},
+		tensor.WithBacking([]bool{true, false, true, false, false, true}),
+	)
+
+	// Tensor operands of the same shape
+	x := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6}),
+	)
+	y := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{-1, -2, -3, -4, -5, -6}),
+	)
+	target := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{1, -2, 3, -4, -5, 6}),
+	)
+
+	out, err := Where(cond, x, y)
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", target, out)
+	}
+
+	// Broadcast row vector and scalar operands
+	row := tensor.NewDense(
+		tensor.Float32,
+		[]int{3},
+		tensor.WithBacking([]float32{1, 2, 3}),
+	)
+	f32Target := tensor.NewDense(
+		tensor.Float32,
+		[]int{2, 3},
+		tensor.WithBacking([]float32{1, 0, 3, 0, 0, 3}),
+	)
+
+	out, err = Where(cond, row, float32(0))
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(f32Target) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", f32Target, out)
+	}
+
+	// Integer operands of different types are converted to int
+	col := tensor.NewDense(
+		tensor.Uint8,
+		[]int{2, 1},
+		tensor.WithBacking([]uint8{7, 8}),
+	)
+	intTarget := tensor.NewDense(
+		tensor.Int,
+		[]int{2, 3},
+		tensor.WithBacking([]int{7, -1, 7, -1, -1, 8}),
+	)
+
+	out, err = Where(cond, col, int32(-1))
+	if err != nil {
+		t.Error(err)
+	}
+	if !out.Eq(intTarget) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", intTarget, out)
+	}
+
+	// Mismatched floating point types and illegal shapes are errors
+	if _, err := Where(cond, x, float32(0)); err == nil {
+		t.Error("expected error for mismatched operand types")
+	}
+	bad := tensor.NewDense(tensor.Float64, []int{2})
+	if _, err := Where(cond, bad, 0.0); err == nil {
+		t.Error("expected error for non-broadcastable shapes")
+	}
+}
+
+func TestWhereB(t *testing.T) {
+	cond := tensor.NewDense(
+		tensor.Bool,
+		[]int{2, 3},
+		tensor.WithBacking([]bool{true, false, true, false, false, true}),
+	)
+	grad := tensor.NewDense(
+		tensor.Float64,
+		[]int{2, 3},
+		tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6}),
+	)
+
+	// x was a row vector broadcast along the first dimension and y
+	// was a scalar
+	dxTarget := tensor.NewDense(
+		tensor.Float64,
+		[]int{3},
+		tensor.WithBacking([]float64{1, 0, 9}),
+	)
+	dyTarget := tensor.New(tensor.FromScalar(11.0))
+
+	dx, dy, err := WhereB(grad, cond, tensor.Shape{3}, tensor.ScalarShape())
+	if err != nil {
+		t.Error(err)
+	}
+	if !dx.Eq(dxTarget) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", dxTarget, dx)
+	}
+	if !dy.Eq(dyTarget) {
+		t.Errorf("expected: \n%v \nreceived: \n%v", dyTarget, dy)
+	}
+
+	// Gradient of the wrong shape is an error
+	if _, _, err := WhereB(grad, cond, tensor.Shape{2},
+		tensor.Shape{2}); err == nil {
+		t.Error("expected error for illegal shapes")
+	}
+}